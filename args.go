@@ -0,0 +1,189 @@
+package goflags
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// preprocessArgs checks the flags of the command line arguments before they are parsed.
+//
+// Unknown flags are reported with suggestions of similar flag names, and unambiguous
+// prefixes of long names are expanded when AllowPrefixMatching is enabled.
+// In interspersed mode, positional arguments are moved after the flags.
+func (flagSet *FlagSet) preprocessArgs(args []string) ([]string, error) {
+	result := make([]string, 0, len(args)+1)
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		isPositional := len(arg) < 2 || arg[0] != '-'
+		if !flagSet.Interspersed && (isPositional || arg == "--") {
+			// the standard library stops parsing flags at the first argument
+			return append(result, args[i:]...), nil
+		}
+		if arg == "--" {
+			positional = append(positional, args[i+1:]...)
+			break
+		}
+		if isPositional {
+			positional = append(positional, arg)
+			continue
+		}
+		dashes, name, value, hasValue := splitFlagArg(arg)
+		if flagSet.GNUMode && dashes == "-" {
+			expanded, consumedNext, err := flagSet.expandShortFlags(name, value, hasValue, args[i+1:])
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, expanded...)
+			if consumedNext {
+				i++
+			}
+			continue
+		}
+
+		currentFlag := flagSet.CommandLine.Lookup(name)
+		if flagSet.GNUMode && !flagSet.isLongFlag(name) {
+			// short names require a single dash in GNU mode
+			currentFlag = nil
+		}
+		if currentFlag == nil && name != "h" && name != "help" {
+			expanded, err := flagSet.resolveUnknownFlag(dashes, name)
+			if err != nil {
+				return nil, err
+			}
+			name = expanded
+			currentFlag = flagSet.CommandLine.Lookup(name)
+			arg = dashes + name
+			if hasValue {
+				arg += "=" + value
+			}
+		}
+		result = append(result, arg)
+
+		// the value of a non boolean flag is the next argument
+		if currentFlag != nil && !hasValue && !isBoolFlag(currentFlag.Value) && i+1 < len(args) {
+			i++
+			result = append(result, args[i])
+		}
+	}
+	if len(positional) > 0 {
+		result = append(result, "--")
+		result = append(result, positional...)
+	}
+	return result, nil
+}
+
+// splitFlagArg splits a flag argument into its dashes, name and value
+func splitFlagArg(arg string) (dashes, name, value string, hasValue bool) {
+	name = strings.TrimLeft(arg, "-")
+	dashes = arg[:len(arg)-len(name)]
+	name, value, hasValue = strings.Cut(name, "=")
+	return dashes, name, value, hasValue
+}
+
+// resolveUnknownFlag returns the long name of an unknown flag if it is an unambiguous
+// prefix and prefix matching is enabled, or an error suggesting similar flags
+func (flagSet *FlagSet) resolveUnknownFlag(dashes, name string) (string, error) {
+	display := flagSet.flagName(name)
+	if flagSet.GNUMode {
+		display = dashes + name
+	}
+	prefixMatches := flagSet.longPrefixMatches(name)
+	if flagSet.AllowPrefixMatching && name != "" {
+		if len(prefixMatches) == 1 {
+			return prefixMatches[0], nil
+		}
+		if len(prefixMatches) > 1 {
+			return "", fmt.Errorf("flag provided but not defined: %s\nambiguous flag prefix, did you mean %s?", display, joinWords(flagSet.flagNames(prefixMatches), "or"))
+		}
+	}
+
+	return "", flagSet.unknownFlagError(display, name)
+}
+
+// longPrefixMatches returns the long flag names starting with a prefix
+func (flagSet *FlagSet) longPrefixMatches(prefix string) []string {
+	var matches []string
+	flagSet.CommandLine.VisitAll(func(fl *flag.Flag) {
+		if flagSet.isLongFlag(fl.Name) && strings.HasPrefix(fl.Name, prefix) {
+			matches = append(matches, fl.Name)
+		}
+	})
+	return matches
+}
+
+// unknownFlagError returns the error of an unknown flag with suggestions of similar flags
+func (flagSet *FlagSet) unknownFlagError(display, name string) error {
+	err := fmt.Errorf("flag provided but not defined: %s", display)
+	if suggestions := flagSet.suggestFlags(name, flagSet.longPrefixMatches(name)); len(suggestions) > 0 {
+		err = fmt.Errorf("%w\ndid you mean %s?", err, joinWords(flagSet.flagNames(suggestions), "or"))
+	}
+	return err
+}
+
+// suggestFlags returns the flag names closest to an unknown flag name by edit distance,
+// falling back to the long names starting with the unknown name
+func (flagSet *FlagSet) suggestFlags(name string, prefixMatches []string) []string {
+	var suggestions []string
+	bestDistance := -1
+	flagSet.CommandLine.VisitAll(func(fl *flag.Flag) {
+		distance := editDistance(strings.ToLower(name), strings.ToLower(fl.Name))
+		if distance > maxSuggestionDistance(fl.Name) || distance >= len(name) {
+			return
+		}
+		switch {
+		case bestDistance == -1 || distance < bestDistance:
+			bestDistance = distance
+			suggestions = []string{fl.Name}
+		case distance == bestDistance:
+			suggestions = append(suggestions, fl.Name)
+		}
+	})
+	if len(suggestions) == 0 && name != "" && len(prefixMatches) <= 3 {
+		suggestions = prefixMatches
+	}
+	sort.Strings(suggestions)
+	return suggestions
+}
+
+// maxSuggestionDistance returns the maximum edit distance for a name to be suggested,
+// so short names are only suggested for close typos
+func maxSuggestionDistance(name string) int {
+	switch {
+	case len(name) <= 2:
+		return 1
+	case len(name) <= 5:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// editDistance returns the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// isBoolFlag returns true if the flag does not take a value
+func isBoolFlag(value flag.Value) bool {
+	boolFlag, ok := value.(interface{ IsBoolFlag() bool })
+	return ok && boolFlag.IsBoolFlag()
+}
@@ -0,0 +1,135 @@
+package goflags
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnknownFlagSuggestions(t *testing.T) {
+	flagSet := NewFlagSet()
+	var rateLimit int
+	var target string
+	var silent, stats bool
+	flagSet.IntVarP(&rateLimit, "rate-limit", "rl", 150, "requests per second")
+	flagSet.StringVarP(&target, "target", "u", "", "target to scan")
+	flagSet.BoolVar(&silent, "silent", false, "silent output")
+	flagSet.BoolVar(&stats, "stats", false, "display statistics")
+
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"-rate-limt", "10"}, "flag provided but not defined: -rate-limt\ndid you mean -rate-limit?"},
+		{[]string{"-u", "example.com", "--targte=example.com"}, "flag provided but not defined: -targte\ndid you mean -target?"},
+		{[]string{"-rate"}, "flag provided but not defined: -rate\ndid you mean -rate-limit?"},
+		{[]string{"-sient"}, "flag provided but not defined: -sient\ndid you mean -silent?"},
+		{[]string{"-stat"}, "flag provided but not defined: -stat\ndid you mean -stats?"},
+		{[]string{"-x"}, "flag provided but not defined: -x"},
+		{[]string{"-unrelated"}, "flag provided but not defined: -unrelated"},
+	}
+	for _, test := range tests {
+		_, err := flagSet.preprocessArgs(test.args)
+		require.EqualError(t, err, test.expected)
+	}
+
+	args, err := flagSet.preprocessArgs([]string{"-u", "-rate-limt", "-silent", "positional", "-unknown"})
+	require.Nil(t, err, "values and positional arguments should not be checked")
+	require.Equal(t, []string{"-u", "-rate-limt", "-silent", "positional", "-unknown"}, args)
+
+	tearDown(t.Name())
+}
+
+func TestParseArgumentErrors(t *testing.T) {
+	flagSet := NewFlagSet()
+	flagSet.CommandLine.Init(os.Args[0], flag.ContinueOnError)
+	flagSet.SetConfigFilePath(filepath.Join(t.TempDir(), "config.yaml"))
+	var rateLimit int
+	flagSet.IntVarP(&rateLimit, "rate-limit", "rl", 150, "requests per second")
+
+	err := flagSet.Parse("-rate-limt", "10")
+	require.EqualError(t, err, "flag provided but not defined: -rate-limt\ndid you mean -rate-limit?", "argument errors should be returned with ContinueOnError")
+	require.Equal(t, 150, rateLimit)
+
+	flagSet = NewFlagSet()
+	flagSet.CommandLine.Init(os.Args[0], flag.PanicOnError)
+	flagSet.IntVarP(&rateLimit, "rate-limit", "rl", 150, "requests per second")
+	require.Panics(t, func() { _ = flagSet.Parse("-rate-limt", "10") })
+
+	tearDown(t.Name())
+}
+
+func TestPrefixMatching(t *testing.T) {
+	flagSet := NewFlagSet()
+	flagSet.AllowPrefixMatching = true
+	var rateLimit, retries int
+	var target string
+	var silent, stats bool
+	flagSet.IntVarP(&rateLimit, "rate-limit", "rl", 150, "requests per second")
+	flagSet.IntVar(&retries, "retries", 1, "number of retries")
+	flagSet.StringVarP(&target, "target", "u", "", "target to scan")
+	flagSet.BoolVar(&silent, "silent", false, "silent output")
+	flagSet.BoolVar(&stats, "stats", false, "display statistics")
+
+	args, err := flagSet.preprocessArgs([]string{"-rate", "10", "--tar=example.com", "-si"})
+	require.Nil(t, err)
+	require.Equal(t, []string{"-rate-limit", "10", "--target=example.com", "-silent"}, args)
+
+	_, err = flagSet.preprocessArgs([]string{"-s"})
+	require.EqualError(t, err, "flag provided but not defined: -s\nambiguous flag prefix, did you mean -silent or -stats?")
+
+	_, err = flagSet.preprocessArgs([]string{"-r"})
+	require.EqualError(t, err, "flag provided but not defined: -r\nambiguous flag prefix, did you mean -rate-limit or -retries?")
+
+	require.Nil(t, flagSet.CommandLine.Parse(args))
+	require.Equal(t, 10, rateLimit)
+	require.Equal(t, "example.com", target)
+	require.True(t, silent)
+
+	tearDown(t.Name())
+}
+
+func TestEditDistance(t *testing.T) {
+	require.Equal(t, 0, editDistance("silent", "silent"))
+	require.Equal(t, 1, editDistance("rate-limt", "rate-limit"))
+	require.Equal(t, 2, editDistance("targte", "target"))
+	require.Equal(t, 3, editDistance("", "abc"))
+}
+
+func TestInterspersedArgs(t *testing.T) {
+	flagSet := NewFlagSet()
+	var rateLimit int
+	var target string
+	var silent, stats bool
+	flagSet.IntVarP(&rateLimit, "rate-limit", "rl", 150, "requests per second")
+	flagSet.StringVarP(&target, "target", "u", "", "target to scan")
+	flagSet.BoolVar(&silent, "silent", false, "silent output")
+	flagSet.BoolVar(&stats, "stats", false, "display statistics")
+
+	args, err := flagSet.preprocessArgs([]string{"example.com", "-silent"})
+	require.Nil(t, err)
+	require.Equal(t, []string{"example.com", "-silent"}, args, "flags after positional arguments should be left as is by default")
+
+	flagSet.Interspersed = true
+	args, err = flagSet.preprocessArgs([]string{"example.com", "-silent", "other.com", "-rl", "10", "--", "-stats", "last.com"})
+	require.Nil(t, err)
+	require.Equal(t, []string{"-silent", "-rl", "10", "--", "example.com", "other.com", "-stats", "last.com"}, args)
+
+	require.Nil(t, flagSet.CommandLine.Parse(args))
+	require.Equal(t, []string{"example.com", "other.com", "-stats", "last.com"}, flagSet.CommandLine.Args())
+	require.True(t, silent)
+	require.False(t, stats)
+	require.Equal(t, 10, rateLimit)
+
+	_, err = flagSet.preprocessArgs([]string{"example.com", "-slient"})
+	require.EqualError(t, err, "flag provided but not defined: -slient\ndid you mean -silent?")
+
+	args, err = flagSet.preprocessArgs([]string{"-u", "example.com", "-"})
+	require.Nil(t, err)
+	require.Equal(t, []string{"-u", "example.com", "--", "-"}, args)
+
+	tearDown(t.Name())
+}
@@ -0,0 +1,186 @@
+package goflags
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	fileutil "github.com/projectdiscovery/utils/file"
+)
+
+// ArgData is a named positional argument of the flagset
+type ArgData struct {
+	name  string
+	usage string
+	field interface{}
+	file  bool
+}
+
+// Arg adds a named positional argument bound to a field.
+//
+// The field can be a *string, an *int, or a *[]string which collects all the
+// remaining arguments and must be the last argument. Arguments are required
+// unless ArgsRange allows fewer, and are bound and validated at the end of Parse.
+func (flagSet *FlagSet) Arg(field interface{}, name, usage string) *ArgData {
+	switch field.(type) {
+	case *string, *int, *[]string:
+	default:
+		panic(fmt.Errorf("<%v> argument field must be a *string, *int or *[]string, got %T", name, field))
+	}
+	if reflect.ValueOf(field).IsNil() {
+		panic(fmt.Errorf("field cannot be nil for argument <%v>", name))
+	}
+	if len(flagSet.args) > 0 {
+		if _, ok := flagSet.args[len(flagSet.args)-1].field.(*[]string); ok {
+			panic(fmt.Errorf("<%v> argument cannot follow a variadic argument", name))
+		}
+	}
+	argData := &ArgData{name: name, usage: usage, field: field}
+	flagSet.args = append(flagSet.args, argData)
+	return argData
+}
+
+// File requires the argument to be the path of an existing file
+func (argData *ArgData) File() *ArgData {
+	argData.file = true
+	return argData
+}
+
+// ArgsRange sets the minimum and maximum number of positional arguments.
+// A negative max allows any number of arguments.
+func (flagSet *FlagSet) ArgsRange(minArgs, maxArgs int) {
+	if minArgs < 0 {
+		panic(fmt.Errorf("minimum number of arguments cannot be negative, got %d", minArgs))
+	}
+	if maxArgs >= 0 && minArgs > maxArgs {
+		panic(fmt.Errorf("minimum number of arguments %d is greater than maximum %d", minArgs, maxArgs))
+	}
+	flagSet.argsRange = &[2]int{minArgs, maxArgs}
+}
+
+// argsBounds returns the number of positional arguments accepted by the flagset
+func (flagSet *FlagSet) argsBounds() (int, int) {
+	if flagSet.argsRange != nil {
+		return flagSet.argsRange[0], flagSet.argsRange[1]
+	}
+	count := 0
+	for _, arg := range flagSet.args {
+		if _, ok := arg.field.(*[]string); ok {
+			return count + 1, -1
+		}
+		count++
+	}
+	return count, count
+}
+
+// bindArgs validates the number of positional arguments and binds them to their fields
+func (flagSet *FlagSet) bindArgs() []error {
+	if len(flagSet.args) == 0 && flagSet.argsRange == nil {
+		return nil
+	}
+	args := flagSet.CommandLine.Args()
+	minArgs, maxArgs := flagSet.argsBounds()
+	if len(args) < minArgs {
+		return []error{fmt.Errorf("expected at least %d arguments, got %d", minArgs, len(args))}
+	}
+	if maxArgs >= 0 && len(args) > maxArgs {
+		return []error{fmt.Errorf("expected at most %d arguments, got %d", maxArgs, len(args))}
+	}
+
+	var errs []error
+	for i, argData := range flagSet.args {
+		if i >= len(args) {
+			break
+		}
+		values := args[i : i+1]
+		if _, ok := argData.field.(*[]string); ok {
+			values = args[i:]
+		}
+		if err := argData.set(values); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// set validates the values of the argument and binds them to its field
+func (argData *ArgData) set(values []string) error {
+	if argData.file {
+		for _, value := range values {
+			if !fileutil.FileExists(value) {
+				return fmt.Errorf("invalid value %q for argument <%s>: file does not exist", value, argData.name)
+			}
+		}
+	}
+	switch field := argData.field.(type) {
+	case *string:
+		*field = values[0]
+	case *int:
+		number, err := strconv.Atoi(values[0])
+		if err != nil {
+			return fmt.Errorf("invalid value %q for argument <%s>: must be an integer", values[0], argData.name)
+		}
+		*field = number
+	case *[]string:
+		*field = append([]string{}, values...)
+	}
+	return nil
+}
+
+// argsUsage returns the positional arguments of the usage line, e.g. "<target> [<output>]"
+func (flagSet *FlagSet) argsUsage() string {
+	minArgs, _ := flagSet.argsBounds()
+	var parts []string
+	for i, argData := range flagSet.args {
+		part := "<" + argData.name + ">"
+		if _, ok := argData.field.(*[]string); ok {
+			part += "..."
+		}
+		if i >= minArgs {
+			part = "[" + part + "]"
+		}
+		parts = append(parts, part)
+	}
+	if len(parts) == 0 && flagSet.argsRange != nil {
+		parts = append(parts, "[args...]")
+	}
+	return strings.Join(parts, " ")
+}
+
+// writeUsageLine writes the usage line of the tool with its positional arguments
+func (flagSet *FlagSet) writeUsageLine(w io.Writer, toolName string) {
+	fmt.Fprintf(w, "Usage:\n  %s [flags]", toolName)
+	if argsUsage := flagSet.argsUsage(); argsUsage != "" {
+		fmt.Fprintf(w, " %s", argsUsage)
+	}
+	fmt.Fprintf(w, "\n\n")
+
+	if len(flagSet.args) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "Arguments:\n")
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, argData := range flagSet.args {
+		fmt.Fprintf(writer, "   <%s>\t%s\t%s\n", argData.name, argData.kind(), argData.usage)
+	}
+	writer.Flush()
+	fmt.Fprintf(w, "\n")
+}
+
+// kind returns the type of the argument displayed in usage
+func (argData *ArgData) kind() string {
+	kind := "string"
+	if argData.file {
+		kind = "file"
+	}
+	switch argData.field.(type) {
+	case *int:
+		kind = "int"
+	case *[]string:
+		kind += "[]"
+	}
+	return kind
+}
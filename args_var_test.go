@@ -0,0 +1,122 @@
+package goflags
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPositionalArgs(t *testing.T) {
+	templateFile := filepath.Join(t.TempDir(), "template.yaml")
+	require.Nil(t, os.WriteFile(templateFile, []byte("id: test"), 0644))
+
+	t.Run("bound", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		flagSet.SetConfigFilePath(filepath.Join(t.TempDir(), "config.yaml"))
+		var silent bool
+		var target string
+		var port int
+		var templates []string
+		flagSet.BoolVar(&silent, "silent", false, "silent output")
+		flagSet.Arg(&target, "target", "target to scan")
+		flagSet.Arg(&port, "port", "port to scan")
+		flagSet.Arg(&templates, "templates", "templates to run").File()
+
+		require.Nil(t, flagSet.Parse("-silent", "example.com", "443", templateFile, templateFile))
+		require.True(t, silent)
+		require.Equal(t, "example.com", target)
+		require.Equal(t, 443, port)
+		require.Equal(t, []string{templateFile, templateFile}, templates)
+		tearDown(t.Name())
+	})
+
+	t.Run("count", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		flagSet.SetConfigFilePath(filepath.Join(t.TempDir(), "config.yaml"))
+		var target string
+		var port int
+		var templates []string
+		flagSet.Arg(&target, "target", "target to scan")
+		flagSet.Arg(&port, "port", "port to scan")
+		flagSet.Arg(&templates, "templates", "templates to run").File()
+
+		require.EqualError(t, flagSet.Parse("example.com", "443"), "expected at least 3 arguments, got 2")
+		tearDown(t.Name())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		flagSet.SetConfigFilePath(filepath.Join(t.TempDir(), "config.yaml"))
+		var target string
+		var port int
+		var templates []string
+		flagSet.Arg(&target, "target", "target to scan")
+		flagSet.Arg(&port, "port", "port to scan")
+		flagSet.Arg(&templates, "templates", "templates to run").File()
+
+		require.EqualError(t, flagSet.Parse("example.com", "https", "missing.yaml"), "invalid value \"https\" for argument <port>: must be an integer\n"+
+			"invalid value \"missing.yaml\" for argument <templates>: file does not exist")
+		tearDown(t.Name())
+	})
+
+	t.Run("range", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		flagSet.SetConfigFilePath(filepath.Join(t.TempDir(), "config.yaml"))
+		var target string
+		var port int
+		flagSet.Arg(&target, "target", "target to scan")
+		flagSet.Arg(&port, "port", "port to scan")
+		flagSet.ArgsRange(1, 2)
+
+		require.Nil(t, flagSet.Parse("example.org"))
+		require.Equal(t, "example.org", target)
+
+		require.EqualError(t, flagSet.Parse("a", "1", "b"), "expected at most 2 arguments, got 3")
+
+		require.PanicsWithError(t, "minimum number of arguments 3 is greater than maximum 2", func() { flagSet.ArgsRange(3, 2) })
+		require.PanicsWithError(t, "minimum number of arguments cannot be negative, got -1", func() { flagSet.ArgsRange(-1, 2) })
+		require.NotPanics(t, func() { flagSet.ArgsRange(3, -1) })
+		tearDown(t.Name())
+	})
+
+	t.Run("usage", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		var target string
+		var port int
+		var templates []string
+		flagSet.Arg(&target, "target", "target to scan")
+		flagSet.Arg(&port, "port", "port to scan")
+		flagSet.Arg(&templates, "templates", "templates to run").File()
+		flagSet.ArgsRange(1, -1)
+
+		output := &bytes.Buffer{}
+		flagSet.CommandLine.SetOutput(output)
+		os.Args = []string{os.Args[0], "-h"}
+		flagSet.usageFunc()
+		require.Contains(t, output.String(), " [flags] <target> [<port>] [<templates>...]\n\n"+
+			"Arguments:\n"+
+			"   <target>     string  target to scan\n"+
+			"   <port>       int     port to scan\n"+
+			"   <templates>  file[]  templates to run\n\n"+
+			"Flags:\n")
+		tearDown(t.Name())
+	})
+
+	t.Run("invalid field", func(t *testing.T) {
+		var value float64
+		var nilString *string
+		var target string
+		var templates []string
+		require.Panics(t, func() { NewFlagSet().Arg(&value, "value", "float value") })
+		require.Panics(t, func() { NewFlagSet().Arg(nilString, "value", "nil value") })
+		require.Panics(t, func() {
+			flagSet := NewFlagSet()
+			flagSet.Arg(&templates, "templates", "templates to run")
+			flagSet.Arg(&target, "target", "target to scan")
+		})
+		tearDown(t.Name())
+	})
+}
@@ -71,9 +71,11 @@ func (flagSet *FlagSet) AuthVarP(field *string, long, short, envKey, usage strin
 		panic(fmt.Errorf("field cannot be nil for flag -%v", long))
 	}
 
+	var source ValueSource
 	if envKey != "" {
 		if envValue := os.Getenv(envKey); envValue != "" {
 			*field = envValue
+			source = SourceEnv
 		}
 	}
 
@@ -86,12 +88,14 @@ func (flagSet *FlagSet) AuthVarP(field *string, long, short, envKey, usage strin
 		usage:        usage,
 		long:         long,
 		defaultValue: "",
+		envName:      envKey,
+		source:       source,
 	}
 
 	if short != "" {
 		flagData.short = short
 		flagSet.CommandLine.Var(authVar, short, usage)
-		flagSet.flagKeys.Set(short, flagData)
+		flagSet.setShortFlag(short, flagData)
 	}
 	flagSet.CommandLine.Var(authVar, long, usage)
 	flagSet.flagKeys.Set(long, flagData)
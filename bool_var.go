@@ -0,0 +1,155 @@
+package goflags
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+)
+
+// negatedPrefix is the prefix of the flags registered for negatable booleans
+const negatedPrefix = "no-"
+
+// Negatable registers a -no-<name> flag setting the boolean flag to false,
+// shown in the help as -[no-]<name>. The negation is registered when the
+// flags are parsed.
+func (flagData *FlagData) Negatable() *FlagData {
+	flagData.negatable = true
+	return flagData
+}
+
+// negatedBool is the value of the negation of a boolean flag
+type negatedBool struct {
+	data   *FlagData
+	target flag.Value
+}
+
+func (n *negatedBool) Set(value string) error {
+	negated, err := strconv.ParseBool(value)
+	if err != nil {
+		return err
+	}
+	return n.target.Set(strconv.FormatBool(!negated))
+}
+
+func (n *negatedBool) String() string {
+	return "false"
+}
+
+func (n *negatedBool) IsBoolFlag() bool {
+	return true
+}
+
+// registerNegations registers the -no-<name> flags of the negatable boolean flags
+func (flagSet *FlagSet) registerNegations() {
+	uniqueDeduper := newUniqueDeduper()
+	flagSet.flagKeys.forEach(func(key string, data *FlagData) {
+		if !data.negatable || data.long == "" || !uniqueDeduper.isUnique(data) {
+			return
+		}
+		name := negatedPrefix + data.long
+		if flagSet.CommandLine.Lookup(name) != nil {
+			return
+		}
+		currentFlag := flagSet.lookupFlag(data)
+		if !isBoolFlag(currentFlag.Value) {
+			panic(fmt.Errorf("-%v flag must be a boolean to be negatable", data.long))
+		}
+		flagSet.CommandLine.Var(&negatedBool{data: data, target: currentFlag.Value}, name, "disable "+data.long)
+	})
+}
+
+// isNegation returns true if the name is the negation of a negatable boolean flag
+func (flagSet *FlagSet) isNegation(name string) bool {
+	if currentFlag := flagSet.CommandLine.Lookup(name); currentFlag != nil {
+		_, ok := currentFlag.Value.(*negatedBool)
+		return ok
+	}
+	return false
+}
+
+// displayNames returns the dash prefixed names of a flag as displayed in usage,
+// e.g. "-nc", "-[no-]color"
+func (flagSet *FlagSet) displayNames(data *FlagData) []string {
+	var names []string
+	if !isEmpty(data.short) {
+		names = append(names, flagSet.flagName(data.short))
+	}
+	if !isEmpty(data.long) {
+		if data.negatable {
+			names = append(names, flagSet.flagName("["+negatedPrefix+"]"+data.long))
+		} else {
+			names = append(names, flagSet.flagName(data.long))
+		}
+	}
+	return names
+}
+
+// OptionalBool is a boolean flag value which distinguishes an unset value from false
+type OptionalBool struct {
+	value *bool
+}
+
+// IsSet returns true if the value was set by any source
+func (o *OptionalBool) IsSet() bool {
+	return o.value != nil
+}
+
+// Bool returns the value, false when it is unset
+func (o *OptionalBool) Bool() bool {
+	return o.value != nil && *o.value
+}
+
+// ValueOr returns the value, or defaultValue when it is unset
+func (o *OptionalBool) ValueOr(defaultValue bool) bool {
+	if o.value == nil {
+		return defaultValue
+	}
+	return *o.value
+}
+
+func (o *OptionalBool) Set(value string) error {
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return err
+	}
+	o.value = &parsed
+	return nil
+}
+
+func (o *OptionalBool) String() string {
+	if o == nil || o.value == nil {
+		return ""
+	}
+	return strconv.FormatBool(*o.value)
+}
+
+func (o *OptionalBool) IsBoolFlag() bool {
+	return true
+}
+
+// OptionalBoolVar adds a boolean flag with a longname which is unset unless
+// given on the command line, through the config file or by another source
+func (flagSet *FlagSet) OptionalBoolVar(field *OptionalBool, long string, usage string) *FlagData {
+	return flagSet.OptionalBoolVarP(field, long, "", usage)
+}
+
+// OptionalBoolVarP adds a boolean flag with a shortname and longname which is
+// unset unless given on the command line, through the config file or by another source
+func (flagSet *FlagSet) OptionalBoolVarP(field *OptionalBool, long, short string, usage string) *FlagData {
+	if field == nil {
+		panic(fmt.Errorf("field cannot be nil for flag -%v", long))
+	}
+	flagData := &FlagData{
+		usage:        usage,
+		long:         long,
+		defaultValue: "",
+	}
+	if short != "" {
+		flagData.short = short
+		flagSet.CommandLine.Var(field, short, usage)
+		flagSet.setShortFlag(short, flagData)
+	}
+	flagSet.CommandLine.Var(field, long, usage)
+	flagSet.flagKeys.Set(long, flagData)
+	return flagData
+}
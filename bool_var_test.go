@@ -0,0 +1,83 @@
+package goflags
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegatableBoolVar(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.Nil(t, os.WriteFile(configFile, []byte("color: true\nno-color: false\n"), 0644))
+
+	flagSet := NewFlagSet()
+	flagSet.SetConfigFilePath(configFile)
+	var color, verbose bool
+	colorFlag := flagSet.BoolVarP(&color, "color", "c", true, "colorize the output").Negatable()
+	flagSet.BoolVar(&verbose, "verbose", false, "verbose output")
+
+	require.Nil(t, flagSet.Parse("-no-color"))
+	require.False(t, color, "config should not override the negation given on the command line")
+	require.Equal(t, SourceCLI, colorFlag.Source())
+	require.Equal(t, "-c, -[no-]color", flagSet.joinFlagNames(colorFlag))
+	require.Nil(t, flagSet.CommandLine.Lookup("no-verbose"), "non negatable flags should not register a negation")
+
+	t.Run("gnu-mode", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		flagSet.GNUMode = true
+		flagSet.SetConfigFilePath(filepath.Join(t.TempDir(), "config.yaml"))
+		var color bool
+		colorFlag := flagSet.BoolVar(&color, "color", true, "colorize the output").Negatable()
+
+		require.Nil(t, flagSet.Parse("--no-color"))
+		require.False(t, color)
+		require.Equal(t, "--[no-]color", flagSet.joinFlagNames(colorFlag))
+		tearDown(t.Name())
+	})
+
+	t.Run("non-boolean", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		var threads int
+		flagSet.IntVar(&threads, "threads", 25, "number of threads").Negatable()
+		require.PanicsWithError(t, "-threads flag must be a boolean to be negatable", flagSet.registerNegations)
+		tearDown(t.Name())
+	})
+
+	tearDown(t.Name())
+}
+
+func TestOptionalBoolVar(t *testing.T) {
+	flagSet := NewFlagSet()
+	flagSet.SetConfigFilePath(filepath.Join(t.TempDir(), "config.yaml"))
+	var followRedirects OptionalBool
+	flagSet.OptionalBoolVarP(&followRedirects, "follow-redirects", "fr", "follow http redirects")
+
+	os.Args = []string{os.Args[0]}
+	require.Nil(t, flagSet.Parse())
+	require.False(t, followRedirects.IsSet())
+	require.False(t, followRedirects.Bool())
+	require.True(t, followRedirects.ValueOr(true))
+
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.Nil(t, os.WriteFile(configFile, []byte("follow-redirects: false\n"), 0644))
+
+	flagSet = NewFlagSet()
+	flagSet.SetConfigFilePath(configFile)
+	followRedirects = OptionalBool{}
+	flagSet.OptionalBoolVarP(&followRedirects, "follow-redirects", "fr", "follow http redirects")
+	require.Nil(t, flagSet.Parse())
+	require.True(t, followRedirects.IsSet(), "false from the config file should be set")
+	require.False(t, followRedirects.ValueOr(true))
+
+	flagSet = NewFlagSet()
+	flagSet.SetConfigFilePath(configFile)
+	followRedirects = OptionalBool{}
+	flagSet.OptionalBoolVarP(&followRedirects, "follow-redirects", "fr", "follow http redirects")
+	require.Nil(t, flagSet.Parse("-fr"))
+	require.True(t, followRedirects.IsSet())
+	require.True(t, followRedirects.Bool(), "config should not override command line values")
+
+	tearDown(t.Name())
+}
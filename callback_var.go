@@ -56,7 +56,7 @@ func (flagSet *FlagSet) CallbackVarP(callback CallBackFunc, long, short string,
 	if short != "" {
 		flagData.short = short
 		flagSet.CommandLine.Var(flagData.field, short, usage)
-		flagSet.flagKeys.Set(short, flagData)
+		flagSet.setShortFlag(short, flagData)
 	}
 	flagSet.CommandLine.Var(flagData.field, long, usage)
 	flagSet.flagKeys.Set(long, flagData)
@@ -0,0 +1,98 @@
+// Command portsfrequency generates the ports frequency data of goflags from the
+// open-frequency column of an nmap-services file.
+//
+//	go run ./cmd/portsfrequency -services /usr/share/nmap/nmap-services -output ports_frequency.json
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	servicesPath := flag.String("services", "/usr/share/nmap/nmap-services", "path of the nmap-services file")
+	outputPath := flag.String("output", "ports_frequency.json", "path of the generated frequency data")
+	flag.Parse()
+
+	file, err := os.Open(*servicesPath)
+	if err != nil {
+		log.Fatalf("could not open nmap-services: %s", err)
+	}
+	defer file.Close()
+
+	var frequency struct {
+		TCP []int `json:"tcp"`
+		UDP []int `json:"udp"`
+	}
+	frequency.TCP, frequency.UDP, err = parseServicesFrequency(file)
+	if err != nil {
+		log.Fatalf("could not parse nmap-services: %s", err)
+	}
+	data, err := json.Marshal(frequency)
+	if err != nil {
+		log.Fatalf("could not marshal frequency data: %s", err)
+	}
+	if err := os.WriteFile(*outputPath, data, 0644); err != nil {
+		log.Fatalf("could not write frequency data: %s", err)
+	}
+}
+
+// parseServicesFrequency returns the tcp and udp ports of an nmap-services file
+// with an open frequency, ordered by decreasing frequency then by port
+func parseServicesFrequency(r io.Reader) (tcp, udp []int, err error) {
+	frequencies := map[string]map[int]float64{"tcp": {}, "udp": {}}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			return nil, nil, fmt.Errorf("invalid nmap-services line %q", line)
+		}
+		portValue, protocol, _ := strings.Cut(fields[1], "/")
+		protocolFrequencies, ok := frequencies[protocol]
+		if !ok {
+			continue
+		}
+		port, err := strconv.Atoi(portValue)
+		if err != nil || port < 1 || port > 65535 {
+			return nil, nil, fmt.Errorf("invalid port in nmap-services line %q", line)
+		}
+		frequency, err := strconv.ParseFloat(fields[2], 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid frequency in nmap-services line %q", line)
+		}
+		if frequency > protocolFrequencies[port] {
+			protocolFrequencies[port] = frequency
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	return rankPorts(frequencies["tcp"]), rankPorts(frequencies["udp"]), nil
+}
+
+// rankPorts returns the ports ordered by decreasing frequency then by port
+func rankPorts(frequencies map[int]float64) []int {
+	ports := make([]int, 0, len(frequencies))
+	for port := range frequencies {
+		ports = append(ports, port)
+	}
+	sort.Slice(ports, func(i, j int) bool {
+		if frequencies[ports[i]] != frequencies[ports[j]] {
+			return frequencies[ports[i]] > frequencies[ports[j]]
+		}
+		return ports[i] < ports[j]
+	})
+	return ports
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseServicesFrequency(t *testing.T) {
+	services := `# Fields in this file are: Service name, portnum/protocol, open-frequency, optional comments
+#
+tcpmux	1/tcp	0.001995	# TCP Port Service Multiplexer [rfc-1078]
+ftp	21/tcp	0.197667	# File Transfer [Control]
+ssh	22/tcp	0.182286	# Secure Shell Login
+telnet	23/tcp	0.221265
+domain	53/tcp	0.048463	# Domain Name Server
+domain	53/udp	0.213496	# Domain Name Server
+http	80/tcp	0.484143	# World Wide Web HTTP
+http	80/udp	0.035767
+alt-http	8080/tcp	0.001995
+unused	9/tcp	0.000000
+sctp-echo	7/sctp	0.000000
+snmp	161/udp	0.433467
+`
+	tcp, udp, err := parseServicesFrequency(strings.NewReader(services))
+	require.Nil(t, err)
+	require.Equal(t, []int{80, 23, 21, 22, 53, 1, 8080}, tcp, "tcp ports should be ordered by frequency then port")
+	require.Equal(t, []int{161, 53, 80}, udp, "udp ports should be ordered by frequency then port")
+
+	_, _, err = parseServicesFrequency(strings.NewReader("http\t80/tcp\tmany\n"))
+	require.Error(t, err)
+	_, _, err = parseServicesFrequency(strings.NewReader("http\t70000/tcp\t0.1\n"))
+	require.Error(t, err)
+}
@@ -0,0 +1,410 @@
+package goflags
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/maps"
+)
+
+// completeCommand is the hidden argument used by completion scripts to request
+// dynamic completion candidates from the binary, e.g. "tool __complete -t cve"
+const completeCommand = "__complete"
+
+// completionFlag holds the information required to complete a single flag
+type completionFlag struct {
+	names       []string
+	description string
+	takesValue  bool
+	values      []string
+	files       bool
+	dynamic     bool
+}
+
+var invalidIdentifierChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// Complete sets a function returning completion candidates for the value of the flag.
+//
+// The generated completion scripts call back into the binary for such flags, so
+// candidates can be computed at runtime (e.g. template IDs or profile names).
+// For comma separated values the function receives the text after the last comma.
+func (flagData *FlagData) Complete(completer func(prefix string) []string) *FlagData {
+	flagData.completer = completer
+	return flagData
+}
+
+// WriteCompletion writes a shell completion script for the flagset to the writer.
+//
+// Supported shells are bash, zsh, fish and powershell. Long and short flag names
+// are completed, along with the allowed values of enum flags, file paths for
+// string slices reading from files and service names for port flags. Flags with
+// a completion function registered via Complete call back into the binary.
+func (flagSet *FlagSet) WriteCompletion(w io.Writer, shell string) error {
+	toolName := getToolName()
+	flags := flagSet.completionFlags()
+
+	buffer := &bytes.Buffer{}
+	switch strings.ToLower(shell) {
+	case "bash":
+		writeBashCompletion(buffer, toolName, flags)
+	case "zsh":
+		writeZshCompletion(buffer, toolName, flags)
+	case "fish":
+		writeFishCompletion(buffer, toolName, flags)
+	case "powershell", "pwsh":
+		writePowershellCompletion(buffer, toolName, flags)
+	default:
+		return fmt.Errorf("unsupported shell %q: supported shells are bash, zsh, fish and powershell", shell)
+	}
+	_, err := w.Write(buffer.Bytes())
+	return err
+}
+
+// completionFlags returns the completion information for all visible flags of the flagset
+func (flagSet *FlagSet) completionFlags() []completionFlag {
+	var flags []completionFlag
+	for _, group := range flagSet.groupedFlags() {
+		for _, data := range group.flags {
+			currentFlag := flagSet.lookupFlag(data)
+			if currentFlag == nil {
+				continue
+			}
+			completion := completionFlag{description: flagUsage(currentFlag), dynamic: data.completer != nil}
+			for _, name := range []string{data.short, data.long} {
+				if name != "" {
+					completion.names = append(completion.names, flagSet.flagName(name))
+				}
+			}
+			if data.negatable && data.long != "" {
+				completion.names = append(completion.names, flagSet.flagName(negatedPrefix+data.long))
+			}
+			if boolFlag, ok := currentFlag.Value.(interface{ IsBoolFlag() bool }); !ok || !boolFlag.IsBoolFlag() {
+				completion.takesValue = true
+			}
+
+			switch value := currentFlag.Value.(type) {
+			case *EnumVar:
+				completion.values = sortedAllowedTypes(value.allowedTypes)
+			case *EnumSliceVar:
+				completion.values = sortedAllowedTypes(value.allowedTypes)
+			case *Port:
+				completion.values = portCompletionValues()
+			case *StringSlice:
+				completion.files = optionMap[value].IsFromFile != nil
+			}
+			flags = append(flags, completion)
+		}
+	}
+	return flags
+}
+
+// writeCompletionCandidates writes the completion candidates for the last
+// of the given arguments to the writer, one per line
+func (flagSet *FlagSet) writeCompletionCandidates(w io.Writer, args []string) {
+	for _, candidate := range flagSet.completeArgs(args) {
+		fmt.Fprintln(w, candidate)
+	}
+}
+
+// completeArgs returns the completion candidates for the last of the given arguments.
+//
+// Values are completed when the previous argument is a flag with a completion function
+// or a known set of values, flag names are completed when the current word starts with a dash.
+func (flagSet *FlagSet) completeArgs(args []string) []string {
+	var current string
+	if len(args) > 0 {
+		current = args[len(args)-1]
+		args = args[:len(args)-1]
+	}
+
+	if len(args) > 0 && strings.HasPrefix(args[len(args)-1], "-") {
+		if data := flagSet.getFlagByName(strings.TrimLeft(args[len(args)-1], "-")); data != nil {
+			if candidates, ok := flagSet.completeFlagValue(data, current); ok {
+				return candidates
+			}
+		}
+	}
+
+	var candidates []string
+	if strings.HasPrefix(current, "-") {
+		for _, completion := range flagSet.completionFlags() {
+			for _, name := range completion.names {
+				if strings.HasPrefix(name, current) {
+					candidates = append(candidates, name)
+				}
+			}
+		}
+	}
+	return candidates
+}
+
+// completeFlagValue returns the value candidates of a flag matching the current word.
+// The boolean is false when the flag doesn't take a value.
+func (flagSet *FlagSet) completeFlagValue(data *FlagData, current string) ([]string, bool) {
+	currentFlag := flagSet.lookupFlag(data)
+	if currentFlag == nil {
+		return nil, false
+	}
+	if boolFlag, ok := currentFlag.Value.(interface{ IsBoolFlag() bool }); ok && boolFlag.IsBoolFlag() {
+		return nil, false
+	}
+
+	var head string
+	if idx := strings.LastIndex(current, ","); idx != -1 {
+		head, current = current[:idx+1], current[idx+1:]
+	}
+
+	var values []string
+	if data.completer != nil {
+		values = data.completer(current)
+	} else {
+		switch value := currentFlag.Value.(type) {
+		case *EnumVar:
+			values = sortedAllowedTypes(value.allowedTypes)
+		case *EnumSliceVar:
+			values = sortedAllowedTypes(value.allowedTypes)
+		case *Port:
+			values = portCompletionValues()
+		}
+	}
+
+	var candidates []string
+	for _, value := range values {
+		if strings.HasPrefix(value, current) {
+			candidates = append(candidates, head+value)
+		}
+	}
+	return candidates, true
+}
+
+// sortedAllowedTypes returns the allowed values of an enum in sorted order
+func sortedAllowedTypes(allowedTypes AllowdTypes) []string {
+	values := maps.Keys(allowedTypes)
+	sort.Strings(values)
+	return values
+}
+
+// portCompletionValues returns the keywords and service names accepted by port flags
+func portCompletionValues() []string {
+	servicesMutex.RLock()
+	services := maps.Keys(servicesMap)
+	servicesMutex.RUnlock()
+	sort.Strings(services)
+	return append([]string{"full", "top-100", "top-1000", "top-100-udp"}, services...)
+}
+
+func writeBashCompletion(buffer *bytes.Buffer, toolName string, flags []completionFlag) {
+	function := "_" + invalidIdentifierChars.ReplaceAllString(toolName, "_") + "_completion"
+
+	fmt.Fprintf(buffer, "# bash completion for %s\n", toolName)
+	buffer.WriteString("# generated by https://github.com/projectdiscovery/goflags\n\n")
+
+	fmt.Fprintf(buffer, "%s_values() {\n", function)
+	buffer.WriteString("\tlocal cur=\"$2\"\n")
+	buffer.WriteString("\tif [[ \"${cur}\" == *,* ]]; then\n")
+	buffer.WriteString("\t\tCOMPREPLY=($(compgen -P \"${cur%,*},\" -W \"$1\" -- \"${cur##*,}\"))\n")
+	buffer.WriteString("\telse\n")
+	buffer.WriteString("\t\tCOMPREPLY=($(compgen -W \"$1\" -- \"${cur}\"))\n")
+	buffer.WriteString("\tfi\n")
+	buffer.WriteString("}\n\n")
+
+	fmt.Fprintf(buffer, "%s() {\n", function)
+	buffer.WriteString("\tlocal cur prev\n")
+	buffer.WriteString("\tcur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	buffer.WriteString("\tprev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n\n")
+	buffer.WriteString("\tcase \"${prev}\" in\n")
+	var names []string
+	for _, completion := range flags {
+		names = append(names, completion.names...)
+		if !completion.takesValue {
+			continue
+		}
+		fmt.Fprintf(buffer, "\t%s)\n", strings.Join(completion.names, "|"))
+		switch {
+		case completion.dynamic:
+			fmt.Fprintf(buffer, "\t\tmapfile -t COMPREPLY < <(\"${COMP_WORDS[0]}\" %s \"${prev}\" \"${cur}\" 2>/dev/null)\n", completeCommand)
+		case len(completion.values) > 0:
+			fmt.Fprintf(buffer, "\t\t%s_values \"%s\" \"${cur}\"\n", function, strings.Join(completion.values, " "))
+		case completion.files:
+			buffer.WriteString("\t\tCOMPREPLY=($(compgen -f -- \"${cur}\"))\n")
+		}
+		buffer.WriteString("\t\treturn 0\n")
+		buffer.WriteString("\t\t;;\n")
+	}
+	buffer.WriteString("\tesac\n\n")
+	buffer.WriteString("\tif [[ \"${cur}\" == -* ]]; then\n")
+	fmt.Fprintf(buffer, "\t\tCOMPREPLY=($(compgen -W \"%s\" -- \"${cur}\"))\n", strings.Join(names, " "))
+	buffer.WriteString("\tfi\n")
+	buffer.WriteString("}\n\n")
+
+	fmt.Fprintf(buffer, "complete -o default -F %s %s\n", function, toolName)
+}
+
+func writeZshCompletion(buffer *bytes.Buffer, toolName string, flags []completionFlag) {
+	function := "_" + invalidIdentifierChars.ReplaceAllString(toolName, "_")
+
+	fmt.Fprintf(buffer, "#compdef %s\n", toolName)
+	fmt.Fprintf(buffer, "# zsh completion for %s\n", toolName)
+	buffer.WriteString("# generated by https://github.com/projectdiscovery/goflags\n\n")
+
+	fmt.Fprintf(buffer, "%s_dynamic() {\n", function)
+	buffer.WriteString("\tlocal -a candidates\n")
+	fmt.Fprintf(buffer, "\tcandidates=(\"${(@f)$(${words[1]} %s \"$1\" \"$PREFIX\" 2>/dev/null)}\")\n", completeCommand)
+	buffer.WriteString("\tcompadd -- \"${candidates[@]}\"\n")
+	buffer.WriteString("}\n\n")
+
+	fmt.Fprintf(buffer, "%s() {\n", function)
+	buffer.WriteString("\t_arguments \\\n")
+	for _, completion := range flags {
+		description := zshEscape(completion.description)
+		for _, name := range completion.names {
+			spec := name + "[" + description + "]"
+			if completion.takesValue {
+				switch {
+				case completion.dynamic:
+					spec += ":value:{" + function + "_dynamic " + name + "}"
+				case len(completion.values) > 0:
+					escaped := make([]string, 0, len(completion.values))
+					for _, value := range completion.values {
+						escaped = append(escaped, strings.ReplaceAll(value, ":", "\\:"))
+					}
+					spec += ":value:_values -s , value " + strings.Join(escaped, " ")
+				case completion.files:
+					spec += ":file:_files"
+				default:
+					spec += ":value: "
+				}
+			}
+			fmt.Fprintf(buffer, "\t\t%s \\\n", shellSingleQuote(spec))
+		}
+	}
+	buffer.WriteString("\t\t'*:file:_files'\n")
+	buffer.WriteString("}\n\n")
+
+	fmt.Fprintf(buffer, "if [ \"$funcstack[1]\" = \"%s\" ]; then\n", function)
+	fmt.Fprintf(buffer, "\t%s \"$@\"\n", function)
+	buffer.WriteString("else\n")
+	fmt.Fprintf(buffer, "\tcompdef %s %s\n", function, toolName)
+	buffer.WriteString("fi\n")
+}
+
+func writeFishCompletion(buffer *bytes.Buffer, toolName string, flags []completionFlag) {
+	fmt.Fprintf(buffer, "# fish completion for %s\n", toolName)
+	buffer.WriteString("# generated by https://github.com/projectdiscovery/goflags\n\n")
+
+	for _, completion := range flags {
+		fmt.Fprintf(buffer, "complete -c %s", toolName)
+		for _, name := range completion.names {
+			fmt.Fprintf(buffer, " -o %s", strings.TrimPrefix(name, "-"))
+		}
+		fmt.Fprintf(buffer, " -d %s", fishQuote(completion.description))
+		if completion.takesValue {
+			switch {
+			case completion.dynamic:
+				fmt.Fprintf(buffer, " -x -a %s", fishQuote(fmt.Sprintf("(%s %s %s (commandline -ct))", toolName, completeCommand, completion.names[0])))
+			case len(completion.values) > 0:
+				fmt.Fprintf(buffer, " -x -a %s", fishQuote(strings.Join(completion.values, " ")))
+			case completion.files:
+				buffer.WriteString(" -r -F")
+			default:
+				buffer.WriteString(" -r")
+			}
+		}
+		buffer.WriteString("\n")
+	}
+}
+
+func writePowershellCompletion(buffer *bytes.Buffer, toolName string, flags []completionFlag) {
+	fmt.Fprintf(buffer, "# powershell completion for %s\n", toolName)
+	buffer.WriteString("# generated by https://github.com/projectdiscovery/goflags\n\n")
+
+	fmt.Fprintf(buffer, "Register-ArgumentCompleter -Native -CommandName %s -ScriptBlock {\n", powershellQuote(toolName))
+	buffer.WriteString("\tparam($wordToComplete, $commandAst, $cursorPosition)\n\n")
+
+	buffer.WriteString("\t$flags = @(\n")
+	for _, completion := range flags {
+		for _, name := range completion.names {
+			fmt.Fprintf(buffer, "\t\t@{ Name = %s; Description = %s }\n", powershellQuote(name), powershellQuote(completion.description))
+		}
+	}
+	buffer.WriteString("\t)\n")
+	buffer.WriteString("\t$values = @(\n")
+	for _, completion := range flags {
+		if !completion.takesValue || len(completion.values) == 0 {
+			continue
+		}
+		quoted := make([]string, 0, len(completion.values))
+		for _, value := range completion.values {
+			quoted = append(quoted, powershellQuote(value))
+		}
+		for _, name := range completion.names {
+			fmt.Fprintf(buffer, "\t\t@{ Name = %s; Values = @(%s) }\n", powershellQuote(name), strings.Join(quoted, ", "))
+		}
+	}
+	buffer.WriteString("\t)\n")
+	var dynamic []string
+	for _, completion := range flags {
+		if completion.takesValue && completion.dynamic {
+			for _, name := range completion.names {
+				dynamic = append(dynamic, powershellQuote(name))
+			}
+		}
+	}
+	fmt.Fprintf(buffer, "\t$dynamic = @(%s)\n\n", strings.Join(dynamic, ", "))
+
+	buffer.WriteString("\t$previous = $commandAst.CommandElements | Where-Object { $_.Extent.EndOffset -lt $cursorPosition } | Select-Object -Last 1\n")
+	buffer.WriteString("\tif ($previous -and $dynamic -ccontains $previous.ToString()) {\n")
+	fmt.Fprintf(buffer, "\t\t& $commandAst.CommandElements[0].ToString() %s $previous.ToString() $wordToComplete 2>$null | ForEach-Object {\n", completeCommand)
+	buffer.WriteString("\t\t\t[System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)\n")
+	buffer.WriteString("\t\t}\n")
+	buffer.WriteString("\t\treturn\n")
+	buffer.WriteString("\t}\n")
+	buffer.WriteString("\tif ($previous) {\n")
+	buffer.WriteString("\t\t$flagValues = $values | Where-Object { $_.Name -ceq $previous.ToString() } | Select-Object -First 1\n")
+	buffer.WriteString("\t\tif ($flagValues) {\n")
+	buffer.WriteString("\t\t\t$prefix = ''\n")
+	buffer.WriteString("\t\t\t$word = $wordToComplete\n")
+	buffer.WriteString("\t\t\tif ($word.Contains(',')) {\n")
+	buffer.WriteString("\t\t\t\t$prefix = $word.Substring(0, $word.LastIndexOf(',') + 1)\n")
+	buffer.WriteString("\t\t\t\t$word = $word.Substring($word.LastIndexOf(',') + 1)\n")
+	buffer.WriteString("\t\t\t}\n")
+	buffer.WriteString("\t\t\t$flagValues.Values | Where-Object { $_ -clike \"$word*\" } | ForEach-Object {\n")
+	buffer.WriteString("\t\t\t\t[System.Management.Automation.CompletionResult]::new(\"$prefix$_\", $_, 'ParameterValue', $_)\n")
+	buffer.WriteString("\t\t\t}\n")
+	buffer.WriteString("\t\t\treturn\n")
+	buffer.WriteString("\t\t}\n")
+	buffer.WriteString("\t}\n\n")
+
+	buffer.WriteString("\tif ($wordToComplete -like '-*') {\n")
+	buffer.WriteString("\t\t$flags | Where-Object { $_.Name -clike \"$wordToComplete*\" } | ForEach-Object {\n")
+	buffer.WriteString("\t\t\t[System.Management.Automation.CompletionResult]::new($_.Name, $_.Name, 'ParameterName', $_.Description)\n")
+	buffer.WriteString("\t\t}\n")
+	buffer.WriteString("\t}\n")
+	buffer.WriteString("}\n")
+}
+
+// shellSingleQuote quotes a value for POSIX shells using single quotes
+func shellSingleQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// zshEscape escapes the characters of a description that are special to _arguments
+func zshEscape(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`)
+	return replacer.Replace(value)
+}
+
+// fishQuote quotes a value for fish using single quotes
+func fishQuote(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "'", `\'`)
+	return "'" + replacer.Replace(value) + "'"
+}
+
+// powershellQuote quotes a value for powershell using single quotes
+func powershellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
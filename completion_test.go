@@ -0,0 +1,133 @@
+package goflags
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteCompletion(t *testing.T) {
+	flagSet := NewFlagSet()
+	var silent bool
+	var target string
+	var list StringSlice
+	var severity []string
+	var ports Port
+	var templates StringSlice
+	flagSet.BoolVar(&silent, "silent", false, "silent output")
+	flagSet.StringVarP(&target, "target", "u", "", "target [host] to scan")
+	flagSet.StringSliceVarP(&list, "list", "l", nil, "file containing targets", FileStringSliceOptions)
+	flagSet.EnumSliceVarP(&severity, "severity", "s", []EnumVariable{0}, "severity to filter", AllowdTypes{"low": 0, "high": 1})
+	flagSet.PortVarP(&ports, "port", "p", nil, "ports to scan")
+	flagSet.StringSliceVarP(&templates, "templates", "t", nil, "templates to run", CommaSeparatedStringSliceOptions).Complete(func(prefix string) []string {
+		return []string{"cves/", "exposures/", "misconfiguration/"}
+	})
+
+	toolName := getToolName()
+	bashFunction := "_" + invalidIdentifierChars.ReplaceAllString(toolName, "_") + "_completion"
+
+	t.Run("bash", func(t *testing.T) {
+		output := &bytes.Buffer{}
+		require.Nil(t, flagSet.WriteCompletion(output, "bash"))
+		script := output.String()
+
+		require.Contains(t, script, "\t-s|-severity)\n\t\t"+bashFunction+"_values \"high low\" \"${cur}\"\n")
+		require.Contains(t, script, "\t-l|-list)\n\t\tCOMPREPLY=($(compgen -f -- \"${cur}\"))\n")
+		require.Contains(t, script, "\t-u|-target)\n\t\treturn 0\n")
+		require.Contains(t, script, " http ")
+		require.NotContains(t, script, "\t-silent)\n")
+		require.Contains(t, script, "compgen -W \"-silent -u -target -l -list -s -severity -p -port -t -templates\"")
+		require.Contains(t, script, "complete -o default -F "+bashFunction+" "+toolName+"\n")
+	})
+
+	t.Run("zsh", func(t *testing.T) {
+		output := &bytes.Buffer{}
+		require.Nil(t, flagSet.WriteCompletion(output, "zsh"))
+		script := output.String()
+
+		require.Contains(t, script, "#compdef "+toolName+"\n")
+		require.Contains(t, script, "'-silent[silent output]' \\\n")
+		require.Contains(t, script, "'-target[target \\[host\\] to scan]:value: ' \\\n")
+		require.Contains(t, script, "'-list[file containing targets]:file:_files' \\\n")
+		require.Contains(t, script, "'-severity[severity to filter]:value:_values -s , value high low' \\\n")
+	})
+
+	t.Run("fish", func(t *testing.T) {
+		output := &bytes.Buffer{}
+		require.Nil(t, flagSet.WriteCompletion(output, "fish"))
+		script := output.String()
+
+		require.Contains(t, script, "complete -c "+toolName+" -o silent -d 'silent output'\n")
+		require.Contains(t, script, "complete -c "+toolName+" -o u -o target -d 'target [host] to scan' -r\n")
+		require.Contains(t, script, "complete -c "+toolName+" -o l -o list -d 'file containing targets' -r -F\n")
+		require.Contains(t, script, "complete -c "+toolName+" -o s -o severity -d 'severity to filter' -x -a 'high low'\n")
+	})
+
+	t.Run("powershell", func(t *testing.T) {
+		output := &bytes.Buffer{}
+		require.Nil(t, flagSet.WriteCompletion(output, "powershell"))
+		script := output.String()
+
+		require.Contains(t, script, "Register-ArgumentCompleter -Native -CommandName '"+toolName+"'")
+		require.Contains(t, script, "@{ Name = '-target'; Description = 'target [host] to scan' }\n")
+		require.Contains(t, script, "@{ Name = '-severity'; Values = @('high', 'low') }\n")
+	})
+
+	t.Run("dynamic", func(t *testing.T) {
+		output := &bytes.Buffer{}
+		require.Nil(t, flagSet.WriteCompletion(output, "bash"))
+		require.Contains(t, output.String(), "\t-t|-templates)\n\t\tmapfile -t COMPREPLY < <(\"${COMP_WORDS[0]}\" __complete \"${prev}\" \"${cur}\" 2>/dev/null)\n")
+
+		output.Reset()
+		require.Nil(t, flagSet.WriteCompletion(output, "fish"))
+		require.Contains(t, output.String(), "-o t -o templates -d 'templates to run' -x -a '("+toolName+" __complete -t (commandline -ct))'\n")
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		require.NotNil(t, flagSet.WriteCompletion(&bytes.Buffer{}, "tcsh"))
+	})
+
+	tearDown(t.Name())
+}
+
+func TestCompleteArgs(t *testing.T) {
+	flagSet := NewFlagSet()
+	var silent bool
+	var target string
+	var list StringSlice
+	var severity []string
+	var ports Port
+	var templates StringSlice
+	flagSet.BoolVar(&silent, "silent", false, "silent output")
+	flagSet.StringVarP(&target, "target", "u", "", "target [host] to scan")
+	flagSet.StringSliceVarP(&list, "list", "l", nil, "file containing targets", FileStringSliceOptions)
+	flagSet.EnumSliceVarP(&severity, "severity", "s", []EnumVariable{0}, "severity to filter", AllowdTypes{"low": 0, "high": 1})
+	flagSet.PortVarP(&ports, "port", "p", nil, "ports to scan")
+	flagSet.StringSliceVarP(&templates, "templates", "t", nil, "templates to run", CommaSeparatedStringSliceOptions).Complete(func(prefix string) []string {
+		return []string{"cves/", "exposures/", "misconfiguration/"}
+	})
+
+	tests := []struct {
+		args     []string
+		expected []string
+	}{
+		{args: []string{"-t", "ex"}, expected: []string{"exposures/"}},
+		{args: []string{"-templates", "cves/,m"}, expected: []string{"cves/,misconfiguration/"}},
+		{args: []string{"-severity", "h"}, expected: []string{"high"}},
+		{args: []string{"-p", "https-"}, expected: []string{"https-alt", "https-wmap"}},
+		{args: []string{"-u", "exa"}, expected: nil},
+		{args: []string{"-silent", "-se"}, expected: []string{"-severity"}},
+		{args: []string{"-l"}, expected: []string{"-l", "-list"}},
+		{args: []string{"target.com"}, expected: nil},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.expected, flagSet.completeArgs(test.args), "could not get correct candidates for %v", test.args)
+	}
+
+	output := &bytes.Buffer{}
+	flagSet.writeCompletionCandidates(output, []string{"-s", ""})
+	require.Equal(t, "high\nlow\n", output.String())
+
+	tearDown(t.Name())
+}
@@ -0,0 +1,118 @@
+package goflags
+
+import (
+	"fmt"
+	"strings"
+
+	fileutil "github.com/projectdiscovery/utils/file"
+)
+
+// Stdin can be used in place of a flag name in OneRequired to accept
+// input piped to the tool, e.g. OneRequired("target", "list", goflags.Stdin)
+const Stdin = "stdin"
+
+// hasStdin reports whether input is piped to the tool
+var hasStdin = fileutil.HasStdin
+
+type constraintKind int
+
+const (
+	mutuallyExclusive constraintKind = iota
+	oneRequired
+	requiredTogether
+)
+
+// flagConstraint is a rule between flags checked at the end of Parse
+type flagConstraint struct {
+	kind  constraintKind
+	names []string
+}
+
+// MutuallyExclusive adds a rule which allows at most one of the flags to be set.
+// The flags must be defined before the rule is added.
+func (flagSet *FlagSet) MutuallyExclusive(names ...string) {
+	flagSet.addConstraint(mutuallyExclusive, names)
+}
+
+// OneRequired adds a rule which requires at least one of the flags to be set.
+// Stdin can be given as a name to also accept input piped to the tool.
+// The flags must be defined before the rule is added.
+func (flagSet *FlagSet) OneRequired(names ...string) {
+	flagSet.addConstraint(oneRequired, names)
+}
+
+// RequiredTogether adds a rule which requires either all or none of the flags to be set.
+// The flags must be defined before the rule is added.
+func (flagSet *FlagSet) RequiredTogether(names ...string) {
+	flagSet.addConstraint(requiredTogether, names)
+}
+
+func (flagSet *FlagSet) addConstraint(kind constraintKind, names []string) {
+	if len(names) < 2 {
+		panic(fmt.Errorf("flag constraint requires at least two flags, got %v", names))
+	}
+	for _, name := range names {
+		if name != Stdin && flagSet.getFlagByName(name) == nil {
+			panic(fmt.Errorf("flag constraint refers to undefined flag -%v", name))
+		}
+	}
+	flagSet.constraints = append(flagSet.constraints, flagConstraint{kind: kind, names: names})
+}
+
+// validateConstraint returns an error describing the violation of a constraint
+func (flagSet *FlagSet) validateConstraint(constraint flagConstraint) error {
+	var set, unset []string
+	for _, name := range constraint.names {
+		if name == Stdin {
+			if hasStdin() {
+				set = append(set, Stdin)
+			} else {
+				unset = append(unset, Stdin)
+			}
+			continue
+		}
+		data := flagSet.getFlagByName(name)
+		if flagSet.isFlagUsed(data) {
+			set = append(set, fmt.Sprintf("%s (%s)", flagSet.flagName(name), data.Source()))
+		} else {
+			unset = append(unset, flagSet.flagName(name))
+		}
+	}
+
+	switch constraint.kind {
+	case mutuallyExclusive:
+		if len(set) > 1 {
+			return fmt.Errorf("flags %s are mutually exclusive", joinWords(set, "and"))
+		}
+	case oneRequired:
+		if len(set) == 0 {
+			return fmt.Errorf("one of %s is required", joinWords(flagSet.flagNames(constraint.names), "or"))
+		}
+	case requiredTogether:
+		if len(set) > 0 && len(unset) > 0 {
+			return fmt.Errorf("flags %s must be used together: %s given without %s", joinWords(flagSet.flagNames(constraint.names), "and"), joinWords(set, "and"), joinWords(unset, "and"))
+		}
+	}
+	return nil
+}
+
+// flagNames returns the dash prefixed names of the flags of a constraint
+func (flagSet *FlagSet) flagNames(names []string) []string {
+	var result []string
+	for _, name := range names {
+		if name == Stdin {
+			result = append(result, Stdin)
+		} else {
+			result = append(result, flagSet.flagName(name))
+		}
+	}
+	return result
+}
+
+// joinWords joins items as an english list, e.g. "a, b and c"
+func joinWords(items []string, conjunction string) string {
+	if len(items) < 2 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " " + conjunction + " " + items[len(items)-1]
+}
@@ -0,0 +1,106 @@
+package goflags
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlagConstraints(t *testing.T) {
+	oldHasStdin := hasStdin
+	defer func() { hasStdin = oldHasStdin }()
+	hasStdin = func() bool { return false }
+
+	t.Run("valid", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		flagSet.SetConfigFilePath(filepath.Join(t.TempDir(), "config.yaml"))
+		var target, list, proxy, proxyAuth string
+		var jsonOutput, csvOutput bool
+		flagSet.StringVarP(&target, "target", "u", "", "target to scan")
+		flagSet.StringVarP(&list, "list", "l", "", "list of targets")
+		flagSet.BoolVar(&jsonOutput, "json", false, "json output")
+		flagSet.BoolVar(&csvOutput, "csv", false, "csv output")
+		flagSet.StringVar(&proxy, "proxy", "", "proxy to use")
+		flagSet.StringVar(&proxyAuth, "proxy-auth", "", "proxy credentials")
+		flagSet.MutuallyExclusive("json", "csv")
+		flagSet.OneRequired("target", "list", Stdin)
+		flagSet.RequiredTogether("proxy", "proxy-auth")
+
+		require.Nil(t, flagSet.Parse("-u", "example.com", "-json", "-proxy", "http://proxy", "-proxy-auth", "user:pass"))
+		tearDown(t.Name())
+	})
+
+	t.Run("violations", func(t *testing.T) {
+		configFile := filepath.Join(t.TempDir(), "config.yaml")
+		require.Nil(t, os.WriteFile(configFile, []byte("csv: true\n"), 0644))
+
+		flagSet := NewFlagSet()
+		flagSet.SetConfigFilePath(configFile)
+		var target, list, proxy, proxyAuth string
+		var jsonOutput, csvOutput bool
+		flagSet.StringVarP(&target, "target", "u", "", "target to scan")
+		flagSet.StringVarP(&list, "list", "l", "", "list of targets")
+		flagSet.BoolVar(&jsonOutput, "json", false, "json output")
+		flagSet.BoolVar(&csvOutput, "csv", false, "csv output")
+		flagSet.StringVar(&proxy, "proxy", "", "proxy to use")
+		flagSet.StringVar(&proxyAuth, "proxy-auth", "", "proxy credentials")
+		flagSet.MutuallyExclusive("json", "csv")
+		flagSet.OneRequired("target", "list", Stdin)
+		flagSet.RequiredTogether("proxy", "proxy-auth")
+
+		err := flagSet.Parse("-json", "-proxy", "http://proxy")
+		require.NotNil(t, err)
+		require.Equal(t, "flags -json (cli) and -csv (config) are mutually exclusive\n"+
+			"one of -target, -list or stdin is required\n"+
+			"flags -proxy and -proxy-auth must be used together: -proxy (cli) given without -proxy-auth", err.Error())
+		tearDown(t.Name())
+	})
+
+	t.Run("false bools", func(t *testing.T) {
+		configFile := filepath.Join(t.TempDir(), "config.yaml")
+		require.Nil(t, os.WriteFile(configFile, []byte("json: false\n"), 0644))
+
+		flagSet := NewFlagSet()
+		flagSet.SetConfigFilePath(configFile)
+		var jsonOutput, csvOutput bool
+		flagSet.BoolVar(&jsonOutput, "json", false, "json output")
+		flagSet.BoolVar(&csvOutput, "csv", false, "csv output")
+		flagSet.MutuallyExclusive("json", "csv")
+		require.Nil(t, flagSet.Parse("-csv"), "json set to false in the config should not be used")
+
+		flagSet = NewFlagSet()
+		flagSet.SetConfigFilePath(filepath.Join(t.TempDir(), "config.yaml"))
+		flagSet.BoolVar(&jsonOutput, "json", false, "json output")
+		flagSet.BoolVar(&csvOutput, "csv", false, "csv output")
+		flagSet.OneRequired("json", "csv")
+		require.EqualError(t, flagSet.Parse("-json=false"), "one of -json or -csv is required", "json set to false on the command line should not be used")
+		tearDown(t.Name())
+	})
+
+	t.Run("stdin", func(t *testing.T) {
+		hasStdin = func() bool { return true }
+		defer func() { hasStdin = func() bool { return false } }()
+
+		flagSet := NewFlagSet()
+		flagSet.SetConfigFilePath(filepath.Join(t.TempDir(), "config.yaml"))
+		var target, list string
+		flagSet.StringVarP(&target, "target", "u", "", "target to scan")
+		flagSet.StringVarP(&list, "list", "l", "", "list of targets")
+		flagSet.OneRequired("target", "list", Stdin)
+
+		os.Args = []string{os.Args[0]}
+		require.Nil(t, flagSet.Parse())
+		tearDown(t.Name())
+	})
+
+	t.Run("undefined flag", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		var jsonOutput bool
+		flagSet.BoolVar(&jsonOutput, "json", false, "json output")
+		require.PanicsWithError(t, "flag constraint refers to undefined flag -xml", func() { flagSet.MutuallyExclusive("json", "xml") }, "undefined flags should be reported when the rule is added")
+		require.NotPanics(t, func() { flagSet.OneRequired("json", Stdin) })
+		tearDown(t.Name())
+	})
+}
@@ -0,0 +1,95 @@
+package goflags
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// countValue is a flag value incremented every time the flag is given
+type countValue struct {
+	value *int
+	data  *FlagData
+}
+
+func (c *countValue) Set(value string) error {
+	switch value {
+	case "true":
+		*c.value++
+		return nil
+	case "false":
+		*c.value = 0
+		return nil
+	}
+	if count, err := strconv.Atoi(value); err == nil {
+		if count < 0 {
+			return fmt.Errorf("count cannot be negative")
+		}
+		*c.value = count
+		return nil
+	}
+	for i, level := range c.data.levels {
+		if strings.EqualFold(level, value) {
+			*c.value = i + 1
+			return nil
+		}
+	}
+	if len(c.data.levels) > 0 {
+		return fmt.Errorf("expected a count or one of %s", strings.Join(c.data.levels, ", "))
+	}
+	return fmt.Errorf("expected a count")
+}
+
+func (c *countValue) String() string {
+	if c == nil || c.value == nil {
+		return "0"
+	}
+	return strconv.Itoa(*c.value)
+}
+
+func (c *countValue) IsBoolFlag() bool {
+	return true
+}
+
+// Levels names the values of a counter flag starting from 1, e.g. Levels("info", "debug", "trace")
+// makes -v info, -vv debug and -vvv trace. The names are shown in help and accepted as values.
+func (flagData *FlagData) Levels(names ...string) *FlagData {
+	flagData.levels = names
+	return flagData
+}
+
+// levelsUsage returns the named levels of a counter flag, e.g. "1=info, 2=debug"
+func levelsUsage(data *FlagData) string {
+	levels := make([]string, 0, len(data.levels))
+	for i, level := range data.levels {
+		levels = append(levels, fmt.Sprintf("%d=%s", i+1, level))
+	}
+	return strings.Join(levels, ", ")
+}
+
+// CountVar adds a counter flag with a longname, incremented every time it is given
+func (flagSet *FlagSet) CountVar(field *int, long string, usage string) *FlagData {
+	return flagSet.CountVarP(field, long, "", usage)
+}
+
+// CountVarP adds a counter flag with a shortname and longname, incremented every time
+// it is given (-v -v -v, or -vvv in GNU mode). An integer or level name sets the count.
+func (flagSet *FlagSet) CountVarP(field *int, long, short string, usage string) *FlagData {
+	if field == nil {
+		panic(fmt.Errorf("field cannot be nil for flag -%v", long))
+	}
+	*field = 0
+	flagData := &FlagData{
+		usage:        usage,
+		long:         long,
+		defaultValue: "0",
+	}
+	if short != "" {
+		flagData.short = short
+		flagSet.CommandLine.Var(&countValue{value: field, data: flagData}, short, usage)
+		flagSet.setShortFlag(short, flagData)
+	}
+	flagSet.CommandLine.Var(&countValue{value: field, data: flagData}, long, usage)
+	flagSet.flagKeys.Set(long, flagData)
+	return flagData
+}
@@ -0,0 +1,75 @@
+package goflags
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountVar(t *testing.T) {
+	t.Run("repeated", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		flagSet.SetConfigFilePath(filepath.Join(t.TempDir(), "config.yaml"))
+		var verbosity int
+		verboseFlag := flagSet.CountVarP(&verbosity, "verbose", "v", "verbosity level")
+		require.Nil(t, flagSet.Parse("-v", "-v", "-verbose"))
+		require.Equal(t, 3, verbosity)
+		require.Equal(t, SourceCLI, verboseFlag.Source())
+		tearDown(t.Name())
+	})
+
+	t.Run("gnu-mode", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		flagSet.GNUMode = true
+		flagSet.SetConfigFilePath(filepath.Join(t.TempDir(), "config.yaml"))
+		var verbosity int
+		var silent bool
+		flagSet.CountVarP(&verbosity, "verbose", "v", "verbosity level")
+		flagSet.BoolVarP(&silent, "silent", "s", false, "silent output")
+		require.Nil(t, flagSet.Parse("-vvv", "-sv"))
+		require.Equal(t, 4, verbosity)
+		require.True(t, silent)
+		tearDown(t.Name())
+	})
+
+	t.Run("levels", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		flagSet.SetConfigFilePath(filepath.Join(t.TempDir(), "config.yaml"))
+		var verbosity int
+		verboseFlag := flagSet.CountVarP(&verbosity, "verbose", "v", "verbosity level").Levels("info", "debug", "trace")
+		currentFlag := flagSet.CommandLine.Lookup("verbose")
+		require.True(t, strings.HasSuffix(flagSet.createUsageString(verboseFlag, currentFlag), "verbosity level (levels: 1=info, 2=debug, 3=trace)"))
+
+		require.Nil(t, flagSet.Parse("-v=debug"))
+		require.Equal(t, 2, verbosity)
+		require.EqualError(t, currentFlag.Value.Set("loud"), "expected a count or one of info, debug, trace")
+		require.EqualError(t, currentFlag.Value.Set("-1"), "count cannot be negative")
+		tearDown(t.Name())
+	})
+
+	t.Run("config", func(t *testing.T) {
+		configFile := filepath.Join(t.TempDir(), "config.yaml")
+		require.Nil(t, os.WriteFile(configFile, []byte("verbose: 3\n"), 0644))
+
+		flagSet := NewFlagSet()
+		flagSet.SetConfigFilePath(configFile)
+		var verbosity int
+		verboseFlag := flagSet.CountVarP(&verbosity, "verbose", "v", "verbosity level")
+		os.Args = []string{os.Args[0]}
+		require.Nil(t, flagSet.Parse())
+		require.Equal(t, 3, verbosity)
+		require.Equal(t, SourceConfig, verboseFlag.Source())
+
+		flagSet = NewFlagSet()
+		flagSet.GNUMode = true
+		flagSet.SetConfigFilePath(configFile)
+		verboseFlag = flagSet.CountVarP(&verbosity, "verbose", "v", "verbosity level")
+		require.Nil(t, flagSet.Parse("-vv"))
+		require.Equal(t, 2, verbosity, "the command line should take precedence over the config")
+		require.Equal(t, SourceCLI, verboseFlag.Source())
+		tearDown(t.Name())
+	})
+}
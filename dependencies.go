@@ -0,0 +1,127 @@
+package goflags
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// implication is a value set on a flag when another flag is used
+type implication struct {
+	name  string
+	value string
+}
+
+// Requires adds a rule which requires the given flags to be set when the flag is used,
+// e.g. -proxy-auth requires -proxy. Parse panics if a required flag is not defined.
+func (flagData *FlagData) Requires(names ...string) *FlagData {
+	flagData.requires = append(flagData.requires, names...)
+	return flagData
+}
+
+// Implies sets the value of another flag when the flag is used, e.g. -stealth
+// implies -rl 5. Implied values never override a value given by the user on
+// the command line, through an environment variable or in the config file.
+// Parse panics if the implied flag is not defined.
+func (flagData *FlagData) Implies(name, value string) *FlagData {
+	flagData.implies = append(flagData.implies, implication{name: name, value: value})
+	return flagData
+}
+
+// isFlagUsed returns true if the flag was given a value by any source.
+// Boolean flags explicitly set to false are not considered used.
+func (flagSet *FlagSet) isFlagUsed(data *FlagData) bool {
+	if data.Source() == SourceDefault {
+		return false
+	}
+	if currentFlag := flagSet.lookupFlag(data); currentFlag != nil {
+		if getter, ok := currentFlag.Value.(flag.Getter); ok {
+			if value, ok := getter.Get().(bool); ok {
+				return value
+			}
+		}
+	}
+	return true
+}
+
+// checkFlagRules panics if the Requires and Implies rules of the flags refer to
+// undefined flags, as these can only be resolved once all the flags are defined
+func (flagSet *FlagSet) checkFlagRules() {
+	flagSet.flagKeys.forEach(func(key string, data *FlagData) {
+		for _, name := range data.requires {
+			if flagSet.getFlagByName(name) == nil {
+				panic(fmt.Errorf("flag -%v requires undefined flag -%v", primaryName(data), name))
+			}
+		}
+		for _, implied := range data.implies {
+			if flagSet.getFlagByName(implied.name) == nil {
+				panic(fmt.Errorf("flag -%v implies undefined flag -%v", primaryName(data), implied.name))
+			}
+		}
+	})
+}
+
+// applyImplications sets the values implied by the used flags on the flags
+// the user did not set. Implied flags can imply other flags in turn.
+func (flagSet *FlagSet) applyImplications() []error {
+	var errs []error
+	for changed := true; changed; {
+		changed = false
+		uniqueDeduper := newUniqueDeduper()
+		flagSet.flagKeys.forEach(func(key string, data *FlagData) {
+			if len(data.implies) == 0 || !uniqueDeduper.isUnique(data) || !flagSet.isFlagUsed(data) {
+				return
+			}
+			for _, implied := range data.implies {
+				impliedData := flagSet.getFlagByName(implied.name)
+				if impliedData.Source() != SourceDefault {
+					continue
+				}
+				impliedData.source = SourceImplied
+				changed = true
+				if err := flagSet.lookupFlag(impliedData).Value.Set(implied.value); err != nil {
+					errs = append(errs, fmt.Errorf("invalid value %q implied by %s for flag %s: %v", implied.value, flagSet.flagName(primaryName(data)), flagSet.flagName(implied.name), err))
+				}
+			}
+		})
+	}
+	return errs
+}
+
+// validateDependencies returns an error for every used flag missing the flags it requires
+func (flagSet *FlagSet) validateDependencies() []error {
+	var errs []error
+	uniqueDeduper := newUniqueDeduper()
+	flagSet.flagKeys.forEach(func(key string, data *FlagData) {
+		if len(data.requires) == 0 || !uniqueDeduper.isUnique(data) || !flagSet.isFlagUsed(data) {
+			return
+		}
+		var missing []string
+		for _, name := range data.requires {
+			if !flagSet.isFlagUsed(flagSet.getFlagByName(name)) {
+				missing = append(missing, flagSet.flagName(name))
+			}
+		}
+		if len(missing) > 0 {
+			errs = append(errs, fmt.Errorf("flag %s (%s) requires %s", flagSet.flagName(primaryName(data)), data.Source(), joinWords(missing, "and")))
+		}
+	})
+	return errs
+}
+
+// primaryName returns the long name of a flag, or its short name if it has no long name
+func primaryName(data *FlagData) string {
+	if data.long != "" {
+		return data.long
+	}
+	return data.short
+}
+
+// impliedValues returns the implications of a flag formatted for display
+func (flagSet *FlagSet) impliedValues(data *FlagData) string {
+	var values []string
+	for _, implied := range data.implies {
+		values = append(values, fmt.Sprintf("%s %s", flagSet.flagName(implied.name), implied.value))
+	}
+	return strings.Join(values, ", ")
+}
@@ -0,0 +1,146 @@
+package goflags
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlagImplies(t *testing.T) {
+	t.Run("implied values", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		flagSet.SetConfigFilePath(filepath.Join(t.TempDir(), "config.yaml"))
+		var stealth bool
+		var rateLimit, concurrency int
+		flagSet.BoolVar(&stealth, "stealth", false, "stealth mode").Implies("rl", "5").Implies("c", "2")
+		flagSet.IntVarP(&rateLimit, "rate-limit", "rl", 150, "requests per second")
+		flagSet.IntVarP(&concurrency, "concurrency", "c", 25, "concurrent requests")
+
+		require.Nil(t, flagSet.Parse("-stealth", "-c", "10"))
+		require.Equal(t, 5, rateLimit)
+		require.Equal(t, 10, concurrency, "implied value should not override user input")
+		require.Equal(t, SourceImplied, flagSet.getFlagByName("rl").Source())
+		require.Equal(t, SourceCLI, flagSet.getFlagByName("c").Source())
+		tearDown(t.Name())
+	})
+
+	t.Run("config values kept", func(t *testing.T) {
+		configFile := filepath.Join(t.TempDir(), "config.yaml")
+		require.Nil(t, os.WriteFile(configFile, []byte("rate-limit: 20\n"), 0644))
+
+		flagSet := NewFlagSet()
+		flagSet.SetConfigFilePath(configFile)
+		var stealth bool
+		var rateLimit, concurrency int
+		flagSet.BoolVar(&stealth, "stealth", false, "stealth mode").Implies("rl", "5").Implies("c", "2")
+		flagSet.IntVarP(&rateLimit, "rate-limit", "rl", 150, "requests per second")
+		flagSet.IntVarP(&concurrency, "concurrency", "c", 25, "concurrent requests")
+
+		require.Nil(t, flagSet.Parse("-stealth"))
+		require.Equal(t, 20, rateLimit)
+		require.Equal(t, 2, concurrency)
+		tearDown(t.Name())
+	})
+
+	t.Run("chained", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		flagSet.SetConfigFilePath(filepath.Join(t.TempDir(), "config.yaml"))
+		var stealth, paranoid bool
+		var rateLimit, retries int
+		flagSet.BoolVar(&stealth, "stealth", false, "stealth mode").Implies("rl", "5")
+		flagSet.BoolVar(&paranoid, "paranoid", false, "paranoid mode").Implies("stealth", "true").Implies("retries", "0")
+		flagSet.IntVarP(&rateLimit, "rate-limit", "rl", 150, "requests per second")
+		flagSet.IntVar(&retries, "retries", 1, "number of retries")
+
+		require.Nil(t, flagSet.Parse("-paranoid"))
+		require.True(t, stealth)
+		require.Equal(t, 0, retries)
+		require.Equal(t, 5, rateLimit)
+		tearDown(t.Name())
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		flagSet.SetConfigFilePath(filepath.Join(t.TempDir(), "config.yaml"))
+		var stealth bool
+		var rateLimit int
+		flagSet.BoolVar(&stealth, "stealth", false, "stealth mode").Implies("rl", "5")
+		flagSet.IntVarP(&rateLimit, "rate-limit", "rl", 150, "requests per second")
+
+		require.Nil(t, flagSet.Parse("-stealth=false"))
+		require.Equal(t, 150, rateLimit)
+		tearDown(t.Name())
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		flagSet.SetConfigFilePath(filepath.Join(t.TempDir(), "config.yaml"))
+		var stealth bool
+		var rateLimit int
+		flagSet.BoolVar(&stealth, "stealth", false, "stealth mode").Implies("rl", "slow")
+		flagSet.IntVarP(&rateLimit, "rate-limit", "rl", 150, "requests per second")
+
+		err := flagSet.Parse("-stealth")
+		require.NotNil(t, err)
+		require.Contains(t, err.Error(), `invalid value "slow" implied by -stealth for flag -rl`)
+		tearDown(t.Name())
+	})
+
+	t.Run("undefined flag", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		var stealth bool
+		flagSet.BoolVar(&stealth, "stealth", false, "stealth mode").Implies("rl", "5")
+
+		require.PanicsWithError(t, "flag -stealth implies undefined flag -rl", func() { _ = flagSet.Parse("-stealth=false") }, "undefined flags should be reported even if the flag is not used")
+		tearDown(t.Name())
+	})
+
+	t.Run("usage", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		var stealth bool
+		var rateLimit, concurrency int
+		flagSet.BoolVar(&stealth, "stealth", false, "stealth mode").Implies("rl", "5").Implies("c", "2")
+		flagSet.IntVarP(&rateLimit, "rate-limit", "rl", 150, "requests per second")
+		flagSet.IntVarP(&concurrency, "concurrency", "c", 25, "concurrent requests")
+
+		output := &bytes.Buffer{}
+		flagSet.CommandLine.SetOutput(output)
+		os.Args = []string{os.Args[0], "-h", "stealth"}
+		flagSet.usageFunc()
+		require.Contains(t, output.String(), "   Implies:    -rl 5, -c 2\n")
+		tearDown(t.Name())
+	})
+}
+
+func TestFlagRequires(t *testing.T) {
+	flagSet := NewFlagSet()
+	flagSet.SetConfigFilePath(filepath.Join(t.TempDir(), "config.yaml"))
+	var proxy, proxyAuth string
+	flagSet.StringVar(&proxy, "proxy", "", "proxy to use")
+	flagSet.StringVarP(&proxyAuth, "proxy-auth", "pa", "", "proxy credentials").Requires("proxy")
+
+	err := flagSet.Parse("-pa", "user:pass")
+	require.NotNil(t, err)
+	require.Equal(t, "flag -proxy-auth (cli) requires -proxy", err.Error())
+
+	flagSet = NewFlagSet()
+	flagSet.SetConfigFilePath(filepath.Join(t.TempDir(), "config.yaml"))
+	flagSet.StringVar(&proxy, "proxy", "", "proxy to use")
+	flagSet.StringVarP(&proxyAuth, "proxy-auth", "pa", "", "proxy credentials").Requires("proxy")
+	require.Nil(t, flagSet.Parse("-pa", "user:pass", "-proxy", "http://proxy"))
+
+	output := &bytes.Buffer{}
+	flagSet.CommandLine.SetOutput(output)
+	os.Args = []string{os.Args[0], "-h", "proxy-auth"}
+	flagSet.usageFunc()
+	require.Contains(t, output.String(), "   Requires:   -proxy\n")
+
+	flagSet = NewFlagSet()
+	flagSet.StringVarP(&proxyAuth, "proxy-auth", "pa", "", "proxy credentials").Requires("proxy")
+	require.PanicsWithError(t, "flag -proxy-auth requires undefined flag -proxy", func() { _ = flagSet.Parse("-pa", "user:pass") })
+
+	tearDown(t.Name())
+}
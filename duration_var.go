@@ -43,7 +43,7 @@ func (flagSet *FlagSet) DurationVarP(field *time.Duration, long, short string, d
 	if short != "" {
 		flagData.short = short
 		flagSet.CommandLine.Var(newDurationValue(defaultValue, field), short, usage)
-		flagSet.flagKeys.Set(short, flagData)
+		flagSet.setShortFlag(short, flagData)
 	}
 	flagSet.CommandLine.Var(newDurationValue(defaultValue, field), long, usage)
 	flagSet.flagKeys.Set(long, flagData)
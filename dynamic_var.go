@@ -121,7 +121,7 @@ func (flagSet *FlagSet) DynamicVarP(field interface{}, long, short string, defau
 	if short != "" {
 		flagData.short = short
 		flagSet.CommandLine.Var(&dynamicFlag, short, usage)
-		flagSet.flagKeys.Set(short, flagData)
+		flagSet.setShortFlag(short, flagData)
 	}
 	flagSet.CommandLine.Var(&dynamicFlag, long, usage)
 	flagSet.flagKeys.Set(long, flagData)
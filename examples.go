@@ -0,0 +1,178 @@
+package goflags
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	fileutil "github.com/projectdiscovery/utils/file"
+	timeutil "github.com/projectdiscovery/utils/time"
+)
+
+// example is a titled command line showing the usage of a tool
+type example struct {
+	title       string
+	commandLine string
+}
+
+// AddExample adds an example command line for the tool to the flagset.
+//
+// Examples are rendered in the help, markdown and man page output. The command line
+// starts with the name of the tool, e.g. "nuclei -u https://example.com -severity high".
+func (flagSet *FlagSet) AddExample(title, commandLine string) {
+	flagSet.examples = append(flagSet.examples, example{title: title, commandLine: commandLine})
+}
+
+// Example adds an example command line showing the usage of the flag
+func (flagData *FlagData) Example(title, commandLine string) *FlagData {
+	flagData.examples = append(flagData.examples, example{title: title, commandLine: commandLine})
+	return flagData
+}
+
+// allExamples returns the examples of the flagset followed by the examples of its flags
+func (flagSet *FlagSet) allExamples() []example {
+	examples := append([]example{}, flagSet.examples...)
+	uniqueDeduper := newUniqueDeduper()
+	flagSet.flagKeys.forEach(func(key string, data *FlagData) {
+		if uniqueDeduper.isUnique(data) {
+			examples = append(examples, data.examples...)
+		}
+	})
+	return examples
+}
+
+// writeExamples writes the examples section of the usage output
+func writeExamples(w io.Writer, examples []example) {
+	fmt.Fprintf(w, "EXAMPLES:\n")
+	for _, example := range examples {
+		fmt.Fprintf(w, "   %s:\n", example.title)
+		fmt.Fprintf(w, "     $ %s\n", example.commandLine)
+	}
+}
+
+// ValidateExamples parses the command line of every example against the flagset
+// and returns an error describing the examples that are no longer valid.
+//
+// It is meant to be called from tests so examples referring to removed or renamed
+// flags, or using invalid values, are caught before they reach the documentation.
+// Command lines are parsed like Parse does, including GNU mode, negations and
+// interspersed arguments, on a copy of the flags: the values of the flagset are not modified.
+func (flagSet *FlagSet) ValidateExamples() error {
+	var errs []error
+	for _, example := range flagSet.allExamples() {
+		if err := flagSet.validateExample(example.commandLine); err != nil {
+			errs = append(errs, fmt.Errorf("example %q: %w", example.title, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateExample parses a single example command line against a copy of the flags
+func (flagSet *FlagSet) validateExample(commandLine string) error {
+	args := GetArgsFromString(commandLine)
+	// only the last command of a pipeline runs the tool
+	for i := len(args) - 1; i >= 0; i-- {
+		if args[i] == "|" {
+			args = args[i+1:]
+			break
+		}
+	}
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		args = args[1:]
+	}
+
+	exampleFlagSet := flagSet.exampleFlagSet()
+	args, err := exampleFlagSet.preprocessArgs(args)
+	if err != nil {
+		return err
+	}
+	return exampleFlagSet.CommandLine.Parse(args)
+}
+
+// exampleFlagSet returns a copy of the flagset parsing command lines the same way,
+// with flags validating their values without modifying the values of the flagset
+func (flagSet *FlagSet) exampleFlagSet() *FlagSet {
+	exampleFlagSet := *flagSet
+	exampleFlagSet.CommandLine = flag.NewFlagSet(getToolName(), flag.ContinueOnError)
+	exampleFlagSet.CommandLine.SetOutput(io.Discard)
+	flagSet.CommandLine.VisitAll(func(fl *flag.Flag) {
+		// negations are registered again to target the copied flags
+		if _, ok := fl.Value.(*negatedBool); ok {
+			return
+		}
+		exampleFlagSet.CommandLine.Var(&exampleValue{value: fl.Value}, fl.Name, fl.Usage)
+	})
+	exampleFlagSet.registerNegations()
+	return &exampleFlagSet
+}
+
+// exampleValue validates the values given to a flag in an example without
+// modifying the value of the flag itself
+type exampleValue struct {
+	value flag.Value
+}
+
+func (e *exampleValue) String() string {
+	return ""
+}
+
+func (e *exampleValue) IsBoolFlag() bool {
+	boolFlag, ok := e.value.(interface{ IsBoolFlag() bool })
+	return ok && boolFlag.IsBoolFlag()
+}
+
+func (e *exampleValue) Set(value string) error {
+	switch fieldValue := e.value.(type) {
+	case *EnumVar:
+		if _, ok := fieldValue.allowedTypes[value]; !ok {
+			return fmt.Errorf("allowed values are %v", fieldValue.allowedTypes.String())
+		}
+	case *EnumSliceVar:
+		for _, item := range strings.Split(value, ",") {
+			if _, ok := fieldValue.allowedTypes[item]; !ok {
+				return fmt.Errorf("allowed values are %v", fieldValue.allowedTypes.String())
+			}
+		}
+	case *Size:
+		_, err := fileutil.FileSizeToByteLen(value)
+		return err
+	case *RateLimitMap:
+		return (&RateLimitMap{}).Set(value)
+	case *Port:
+		return (&Port{}).Set(value)
+	case *durationValue:
+		_, err := timeutil.ParseDuration(value)
+		return err
+	case flag.Getter:
+		return validateGetterValue(fieldValue.Get(), value)
+	}
+	return nil
+}
+
+// validateGetterValue checks that a value can be parsed as the type of a standard library flag
+func validateGetterValue(current interface{}, value string) error {
+	var err error
+	switch current.(type) {
+	case bool:
+		_, err = strconv.ParseBool(value)
+	case int:
+		_, err = strconv.ParseInt(value, 0, strconv.IntSize)
+	case int64:
+		_, err = strconv.ParseInt(value, 0, 64)
+	case uint:
+		_, err = strconv.ParseUint(value, 0, strconv.IntSize)
+	case uint64:
+		_, err = strconv.ParseUint(value, 0, 64)
+	case float32:
+		_, err = strconv.ParseFloat(value, 32)
+	case float64:
+		_, err = strconv.ParseFloat(value, 64)
+	case time.Duration:
+		_, err = time.ParseDuration(value)
+	}
+	return err
+}
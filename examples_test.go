@@ -0,0 +1,121 @@
+package goflags
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateExamples(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		var target string
+		var threads int
+		var silent bool
+		flagSet.StringVarP(&target, "target", "u", "", "target to scan")
+		flagSet.IntVarP(&threads, "threads", "c", 25, "number of threads").Example("run with more threads", "tool -u example.com -c 50")
+		flagSet.BoolVar(&silent, "silent", false, "silent output")
+		flagSet.AddExample("scan targets from stdin", "cat hosts.txt | tool -silent")
+		require.Nil(t, flagSet.ValidateExamples())
+		tearDown(t.Name())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		var target, severity string
+		var threads int
+		flagSet.StringVarP(&target, "target", "u", "", "target to scan")
+		flagSet.IntVarP(&threads, "threads", "c", 25, "number of threads")
+		flagSet.EnumVar(&severity, "severity", EnumVariable(0), "severity to filter", AllowdTypes{"low": 0, "high": 1})
+		flagSet.AddExample("unknown flag", "tool -target example.com -removed")
+		flagSet.AddExample("invalid enum", "tool -severity critical")
+		flagSet.AddExample("invalid number", "tool -c many")
+
+		err := flagSet.ValidateExamples()
+		require.NotNil(t, err)
+		require.Contains(t, err.Error(), `example "unknown flag": flag provided but not defined: -removed`)
+		require.Contains(t, err.Error(), `example "invalid enum"`)
+		require.Contains(t, err.Error(), `example "invalid number"`)
+		tearDown(t.Name())
+	})
+
+	t.Run("parse options", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		flagSet.GNUMode = true
+		flagSet.Interspersed = true
+		flagSet.SetConfigFilePath(filepath.Join(t.TempDir(), "config.yaml"))
+		var silent, verbose, color bool
+		var threads int
+		flagSet.BoolVarP(&silent, "silent", "s", false, "silent output")
+		flagSet.BoolVarP(&verbose, "verbose", "v", false, "verbose output")
+		flagSet.BoolVar(&color, "color", true, "colorize the output").Negatable()
+		flagSet.IntVarP(&threads, "threads", "c", 25, "number of threads")
+		flagSet.AddExample("grouped short flags", "tool -sv -c10 --no-color target.com")
+		require.Nil(t, flagSet.ValidateExamples(), "examples should be parsed like the command line")
+
+		flagSet.AddExample("unknown flag after target", "tool target.com --bogus")
+		require.EqualError(t, flagSet.ValidateExamples(), `example "unknown flag after target": flag provided but not defined: --bogus`)
+
+		require.Nil(t, flagSet.Parse("-sv", "-c10", "--no-color", "target.com"))
+		require.True(t, silent)
+		require.True(t, verbose)
+		require.False(t, color)
+		require.Equal(t, 10, threads)
+		require.EqualError(t, flagSet.ValidateExamples(), `example "unknown flag after target": flag provided but not defined: --bogus`, "examples should be validated the same after parsing")
+		tearDown(t.Name())
+	})
+
+	t.Run("values untouched", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		var target string
+		var color bool
+		flagSet.StringVarP(&target, "target", "u", "default", "target to scan")
+		flagSet.BoolVar(&color, "color", true, "colorize the output").Negatable()
+		flagSet.AddExample("scan a single target", "tool -u example.com -no-color")
+
+		require.Nil(t, flagSet.ValidateExamples())
+		require.Equal(t, "default", target, "validating examples should not modify values")
+		require.True(t, color, "validating examples should not modify values")
+		tearDown(t.Name())
+	})
+}
+
+func TestExamplesUsage(t *testing.T) {
+	flagSet := NewFlagSet()
+	var target, severity string
+	var threads int
+	flagSet.StringVarP(&target, "target", "u", "", "target to scan")
+	flagSet.IntVarP(&threads, "threads", "c", 25, "number of threads").Example("run with more threads", "tool -u example.com -c 50")
+	flagSet.EnumVar(&severity, "severity", EnumVariable(0), "severity to filter", AllowdTypes{"low": 0, "high": 1})
+	flagSet.AddExample("scan a single target", "tool -u example.com -severity high")
+
+	output := &bytes.Buffer{}
+	flagSet.CommandLine.SetOutput(output)
+	os.Args = []string{os.Args[0], "-h"}
+	flagSet.usageFunc()
+
+	expected := "EXAMPLES:\n" +
+		"   scan a single target:\n" +
+		"     $ tool -u example.com -severity high\n" +
+		"   run with more threads:\n" +
+		"     $ tool -u example.com -c 50\n"
+	require.True(t, strings.HasSuffix(output.String(), "\n\n"+expected), "could not get examples in usage")
+
+	output.Reset()
+	require.Nil(t, flagSet.WriteMarkdown(output))
+	require.Contains(t, output.String(), "\n\n"+expected+"```\n")
+
+	output.Reset()
+	require.Nil(t, flagSet.WriteMarkdownTable(output))
+	require.Contains(t, output.String(), "\n### Examples\n\nscan a single target:\n\n```console\ntool -u example.com -severity high\n```\n")
+
+	output.Reset()
+	require.Nil(t, flagSet.WriteManPage(output, 1))
+	require.Contains(t, output.String(), ".SH EXAMPLES\n.PP\nscan a single target:\n.PP\n.RS\n.nf\ntool \\-u example.com \\-severity high\n.fi\n.RE\n")
+
+	tearDown(t.Name())
+}
@@ -0,0 +1,199 @@
+package goflags
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	fileutil "github.com/projectdiscovery/utils/file"
+)
+
+// LongHelp sets a long-form description of the flag shown on its help page (-h <flag>)
+func (flagData *FlagData) LongHelp(text string) *FlagData {
+	flagData.longHelp = text
+	return flagData
+}
+
+// displaySingleFlagUsageFunc displays the detailed help page of a single flag
+func (flagSet *FlagSet) displaySingleFlagUsageFunc(_ string, data *FlagData, cliOutput io.Writer, writer *tabwriter.Writer) {
+	currentFlag := flagSet.lookupFlag(data)
+	if currentFlag == nil {
+		return
+	}
+	valueType := reflect.TypeOf(currentFlag.Value)
+
+	fmt.Fprint(cliOutput, flagSet.joinFlagNames(data))
+	if flagType := usageFlagType(currentFlag, valueType); flagType != "" {
+		fmt.Fprintf(cliOutput, " %s", flagType)
+	}
+	fmt.Fprintf(cliOutput, "\n")
+	for _, line := range strings.Split(data.usage, "\n") {
+		fmt.Fprintf(cliOutput, "   %s\n", line)
+	}
+	if !isEmpty(data.longHelp) {
+		fmt.Fprintf(cliOutput, "\n")
+		for _, line := range strings.Split(strings.TrimSpace(data.longHelp), "\n") {
+			fmt.Fprintf(cliOutput, "   %s\n", line)
+		}
+	}
+	fmt.Fprintf(cliOutput, "\n")
+
+	fmt.Fprintf(writer, "   Type:\t%s\n", flagKind(currentFlag.Value))
+	if defaultValue := usageDefaultValue(data, currentFlag, valueType); defaultValue != "" {
+		fmt.Fprintf(writer, "   Default:\t%s\n", defaultValue)
+	}
+	if _, isCallback := currentFlag.Value.(*callBackVar); !isCallback {
+		value, source := flagSet.effectiveValue(data, currentFlag)
+		fmt.Fprintf(writer, "   Value:\t%s (%s)\n", value, source)
+		fmt.Fprintf(writer, "   Config key:\t%s\n", currentFlag.Name)
+	}
+	if data.envName != "" {
+		fmt.Fprintf(writer, "   Env:\t%s\n", data.envName)
+	}
+	if data.group != "" {
+		fmt.Fprintf(writer, "   Group:\t%s\n", flagSet.groupDescription(data.group))
+	}
+	if data.required {
+		fmt.Fprintf(writer, "   Required:\tyes\n")
+	}
+	if len(data.requires) > 0 {
+		fmt.Fprintf(writer, "   Requires:\t%s\n", strings.Join(flagSet.flagNames(data.requires), ", "))
+	}
+	if len(data.implies) > 0 {
+		fmt.Fprintf(writer, "   Implies:\t%s\n", flagSet.impliedValues(data))
+	}
+	switch value := currentFlag.Value.(type) {
+	case *EnumVar:
+		fmt.Fprintf(writer, "   Allowed:\t%s\n", strings.Join(sortedAllowedTypes(value.allowedTypes), ", "))
+	case *EnumSliceVar:
+		fmt.Fprintf(writer, "   Allowed:\t%s\n", strings.Join(sortedAllowedTypes(value.allowedTypes), ", "))
+	}
+	if len(data.levels) > 0 {
+		fmt.Fprintf(writer, "   Levels:\t%s\n", levelsUsage(data))
+	}
+	if syntax := flagSyntax(currentFlag.Value); syntax != "" {
+		fmt.Fprintf(writer, "   Syntax:\t%s\n", syntax)
+	}
+	writer.Flush()
+
+	if len(data.examples) > 0 {
+		fmt.Fprintf(cliOutput, "\n")
+		writeExamples(cliOutput, data.examples)
+	}
+}
+
+// effectiveValue returns the value a flag will have once parsed and its source.
+// Help is displayed while the command line is parsed, before the sources are marked
+// and the config file is merged, so both are resolved without modifying the flagset.
+func (flagSet *FlagSet) effectiveValue(data *FlagData, currentFlag *flag.Flag) (string, ValueSource) {
+	var onCommandLine bool
+	flagSet.CommandLine.Visit(func(fl *flag.Flag) {
+		if negation, ok := fl.Value.(*negatedBool); ok {
+			onCommandLine = onCommandLine || negation.data == data
+		} else {
+			onCommandLine = onCommandLine || flagSet.flagKeys.values[fl.Name] == data
+		}
+	})
+	if onCommandLine {
+		return currentFlag.Value.String(), SourceCLI
+	}
+	if source := data.Source(); source != SourceDefault {
+		return currentFlag.Value.String(), source
+	}
+	if configFilePath, err := flagSet.GetConfigFilePath(); err == nil && fileutil.FileExists(configFilePath) {
+		config, _ := readConfigData(configFilePath)
+		for _, name := range []string{data.long, data.short} {
+			if item, ok := config[name]; ok && name != "" {
+				return formatConfigValue(item), SourceConfig
+			}
+		}
+	}
+	return currentFlag.Value.String(), SourceDefault
+}
+
+// formatConfigValue returns a config file item as a flag value, lists being comma separated
+func formatConfigValue(item interface{}) string {
+	if items, ok := item.([]interface{}); ok {
+		values := make([]string, 0, len(items))
+		for _, value := range items {
+			values = append(values, fmt.Sprint(value))
+		}
+		return strings.Join(values, ",")
+	}
+	return fmt.Sprint(item)
+}
+
+// groupDescription returns the display name of a group
+func (flagSet *FlagSet) groupDescription(name string) string {
+	if group := flagSet.getGroupbyName(name); group.description != "" {
+		return normalizeGroupDescription(group.description)
+	}
+	return name
+}
+
+// flagKind returns a descriptive name of the type of a flag value
+func flagKind(value flag.Value) string {
+	switch fieldValue := value.(type) {
+	case *StringSlice:
+		return "string[]"
+	case *EnumVar:
+		return "enum"
+	case *EnumSliceVar:
+		return "enum[]"
+	case *Port:
+		return "port"
+	case *Size:
+		return "size"
+	case *RateLimitMap:
+		return "rate-limit[]"
+	case *RuntimeMap:
+		return "map"
+	case *callBackVar:
+		return "callback"
+	case *AuthVar:
+		return "auth"
+	case *dynamicFlag:
+		return "dynamic " + reflect.TypeOf(fieldValue.field).Elem().Kind().String()
+	case *durationValue:
+		return "duration"
+	case *OptionalBool:
+		return "optional bool"
+	case *countValue:
+		return "count"
+	case flag.Getter:
+		switch fieldValue.Get().(type) {
+		case time.Duration:
+			return "duration"
+		default:
+			return reflect.TypeOf(fieldValue.Get()).String()
+		}
+	}
+	return "value"
+}
+
+// flagSyntax returns the accepted syntax of flag types with special formats
+func flagSyntax(value flag.Value) string {
+	switch value.(type) {
+	case *Port:
+		return "comma separated ports (80,443), ranges (1-1024, 1000-), service names (http, ftp*), most common ports (top-50, top-1000, top-100-udp), full, protocol prefixes on any of them (U:53,T:1-1024,U:domain) and ! exclusions (top-1000,!22)"
+	case *Size:
+		return "number with an optional kb, mb, gb or tb unit, mb when omitted (512kb, 10mb, 2gb)"
+	case *RateLimitMap:
+		return "key=count/duration of at most a minute (hackertarget=10/s, scanme.sh=100/m)"
+	case *RuntimeMap:
+		return "key=value, or a file containing key=value lines"
+	case *durationValue:
+		return "number with an optional unit, seconds when omitted (30, 500ms, 5m, 1h30m, 2d)"
+	case *dynamicFlag:
+		return "-flag to use the default value, -flag=value to set a value"
+	case *AuthVar:
+		return "-flag value, or -flag alone to be prompted for the value"
+	case *countValue:
+		return "-flag repeated to increase the count (-v -v, -vv in GNU mode), -flag=N to set it"
+	}
+	return ""
+}
@@ -0,0 +1,100 @@
+package goflags
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSingleFlagHelp(t *testing.T) {
+	flagSet := NewFlagSet()
+	var severity string
+	var ports Port
+	var threads int
+	flagSet.CreateGroup("filter", "Filtering",
+		flagSet.EnumVarP(&severity, "severity", "s", EnumVariable(0), "severity to filter", AllowdTypes{"low": 0, "high": 1}).
+			LongHelp("Only results matching the severity are reported.\nMultiple runs can be used for several severities.").
+			Example("report high severity results", "tool -s high"),
+	)
+	flagSet.PortVarP(&ports, "port", "p", nil, "ports to scan")
+	flagSet.IntVarP(&threads, "threads", "c", 25, "number of threads")
+
+	output := &bytes.Buffer{}
+	flagSet.CommandLine.SetOutput(output)
+
+	os.Args = []string{os.Args[0], "-h", "severity"}
+	flagSet.usageFunc()
+
+	expected := "-s, -severity value\n" +
+		"   severity to filter\n" +
+		"\n" +
+		"   Only results matching the severity are reported.\n" +
+		"   Multiple runs can be used for several severities.\n" +
+		"\n" +
+		"   Type:       enum\n" +
+		"   Default:    low\n" +
+		"   Value:      low (default)\n" +
+		"   Config key: severity\n" +
+		"   Group:      FILTERING\n" +
+		"   Allowed:    high, low\n" +
+		"\n" +
+		"EXAMPLES:\n" +
+		"   report high severity results:\n" +
+		"     $ tool -s high\n"
+	require.Equal(t, expected, output.String())
+
+	output.Reset()
+	os.Args = []string{os.Args[0], "-h", "p"}
+	flagSet.usageFunc()
+	require.Contains(t, output.String(), "   Type:       port\n")
+	require.Contains(t, output.String(), "   Syntax:     comma separated ports")
+
+	output.Reset()
+	os.Args = []string{os.Args[0], "-h", "threads"}
+	flagSet.usageFunc()
+	require.Contains(t, output.String(), "   Type:       int\n")
+	require.Contains(t, output.String(), "   Default:    25\n")
+	require.Contains(t, output.String(), "   Value:      25 (default)\n")
+
+	tearDown(t.Name())
+}
+
+func TestSingleFlagHelpValueSources(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.Nil(t, os.WriteFile(configFile, []byte("threads: 50\nseverity: [low, high]\n"), 0644))
+
+	flagSet := NewFlagSet()
+	flagSet.SetConfigFilePath(configFile)
+	var threads, retries int
+	var severity StringSlice
+	threadsFlag := flagSet.IntVarP(&threads, "threads", "c", 25, "number of threads")
+	retriesFlag := flagSet.IntVar(&retries, "retries", 1, "number of retries")
+	flagSet.StringSliceVarP(&severity, "severity", "s", nil, "severities to filter", CommaSeparatedStringSliceOptions)
+
+	output := &bytes.Buffer{}
+	flagSet.CommandLine.SetOutput(output)
+	require.Nil(t, flagSet.CommandLine.Parse([]string{"-retries", "3"}))
+
+	os.Args = []string{os.Args[0], "-h", "threads"}
+	flagSet.usageFunc()
+	require.Contains(t, output.String(), "   Value:      50 (config)\n")
+
+	output.Reset()
+	os.Args = []string{os.Args[0], "-h", "severity"}
+	flagSet.usageFunc()
+	require.Contains(t, output.String(), "   Value:      low,high (config)\n")
+
+	output.Reset()
+	os.Args = []string{os.Args[0], "-h", "retries"}
+	flagSet.usageFunc()
+	require.Contains(t, output.String(), "   Value:      3 (cli)\n")
+
+	require.Equal(t, 25, threads, "help should not merge the config file")
+	require.Equal(t, SourceDefault, threadsFlag.Source(), "help should not modify sources")
+	require.Equal(t, SourceDefault, retriesFlag.Source(), "help should not modify sources")
+
+	tearDown(t.Name())
+}
@@ -0,0 +1,51 @@
+package goflags
+
+import (
+	"errors"
+	"strconv"
+)
+
+type float32Value float32
+
+func newFloat32Value(val float32, p *float32) *float32Value {
+	*p = val
+	return (*float32Value)(p)
+}
+
+func (f *float32Value) Set(s string) error {
+	v, err := strconv.ParseFloat(s, 32)
+	if err != nil {
+		if numError, ok := err.(*strconv.NumError); ok && numError.Err == strconv.ErrRange {
+			return errors.New("value out of range")
+		}
+		return errors.New("parse error")
+	}
+	*f = float32Value(v)
+	return nil
+}
+
+func (f *float32Value) Get() any { return float32(*f) }
+
+func (f *float32Value) String() string { return strconv.FormatFloat(float64(*f), 'g', -1, 32) }
+
+// Float32Var adds a float32 flag with a longname
+func (flagSet *FlagSet) Float32Var(field *float32, long string, defaultValue float32, usage string) *FlagData {
+	return flagSet.Float32VarP(field, long, "", defaultValue, usage)
+}
+
+// Float32VarP adds a float32 flag with a shortname and longname
+func (flagSet *FlagSet) Float32VarP(field *float32, long, short string, defaultValue float32, usage string) *FlagData {
+	flagData := &FlagData{
+		usage:        usage,
+		long:         long,
+		defaultValue: strconv.FormatFloat(float64(defaultValue), 'g', -1, 32),
+	}
+	if short != "" {
+		flagData.short = short
+		flagSet.CommandLine.Var(newFloat32Value(defaultValue, field), short, usage)
+		flagSet.setShortFlag(short, flagData)
+	}
+	flagSet.CommandLine.Var(newFloat32Value(defaultValue, field), long, usage)
+	flagSet.flagKeys.Set(long, flagData)
+	return flagData
+}
@@ -0,0 +1,24 @@
+package goflags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFloat32Var(t *testing.T) {
+	var threshold float32
+	flagSet := NewFlagSet()
+	flagSet.Float32VarP(&threshold, "threshold", "th", 0.25, "match threshold")
+	require.Equal(t, float32(0.25), threshold)
+
+	require.Nil(t, flagSet.CommandLine.Parse([]string{"-th", "0.75"}))
+	require.Equal(t, float32(0.75), threshold)
+
+	err := flagSet.CommandLine.Lookup("threshold").Value.Set("1e39")
+	require.EqualError(t, err, "value out of range")
+	err = flagSet.CommandLine.Lookup("threshold").Value.Set("high")
+	require.EqualError(t, err, "parse error")
+
+	tearDown(t.Name())
+}
@@ -0,0 +1,88 @@
+package goflags
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// setShortFlag registers the short name of a flag, which must be a single
+// character when GNU mode is enabled
+func (flagSet *FlagSet) setShortFlag(short string, flagData *FlagData) {
+	if flagSet.GNUMode && utf8.RuneCountInString(short) != 1 {
+		panic(fmt.Errorf("short name -%v of flag --%v must be a single character in GNU mode", short, flagData.long))
+	}
+	flagSet.flagKeys.Set(short, flagData)
+}
+
+// flagName returns the name of a flag with its dashes, which are doubled
+// for long names in GNU mode
+func (flagSet *FlagSet) flagName(name string) string {
+	if flagSet.GNUMode && utf8.RuneCountInString(name) > 1 {
+		return "--" + name
+	}
+	return "-" + name
+}
+
+// isShortFlag returns true if the name is registered as the short name of a flag
+func (flagSet *FlagSet) isShortFlag(name string) bool {
+	data, ok := flagSet.flagKeys.values[name]
+	return ok && data.short == name
+}
+
+// isLongFlag returns true if the name is registered as the long name of a flag
+// or the negation of a negatable flag
+func (flagSet *FlagSet) isLongFlag(name string) bool {
+	data, ok := flagSet.flagKeys.values[name]
+	return ok && data.long == name || flagSet.isNegation(name)
+}
+
+// expandShortFlags expands a single dash argument of GNU mode into the flags it bundles,
+// e.g. -sv into -s -v and -c10 into -c 10. The returned boolean is true when the
+// next argument was consumed as the value of the last flag.
+func (flagSet *FlagSet) expandShortFlags(name, value string, hasValue bool, next []string) ([]string, bool, error) {
+	if name == "h" && !flagSet.isShortFlag(name) {
+		return []string{"-h"}, false, nil
+	}
+
+	var result []string
+	shorts := []rune(name)
+	for i, short := range shorts {
+		current := string(short)
+		if !flagSet.isShortFlag(current) {
+			if len(shorts) == 1 {
+				return nil, false, flagSet.unknownFlagError("-"+current, current)
+			}
+			err := fmt.Errorf("flag provided but not defined: -%s in -%s", current, name)
+			if flagSet.isLongFlag(name) {
+				err = fmt.Errorf("%w\ndid you mean --%s?", err, name)
+			}
+			return nil, false, err
+		}
+		last := i == len(shorts)-1
+
+		if isBoolFlag(flagSet.CommandLine.Lookup(current).Value) {
+			if last && hasValue {
+				result = append(result, "-"+current+"="+value)
+			} else {
+				result = append(result, "-"+current)
+			}
+			continue
+		}
+		// the remaining characters are the value of a flag taking a value
+		switch {
+		case !last:
+			attached := string(shorts[i+1:])
+			if hasValue {
+				attached += "=" + value
+			}
+			return append(result, "-"+current, attached), false, nil
+		case hasValue:
+			return append(result, "-"+current, value), false, nil
+		case len(next) > 0:
+			return append(result, "-"+current, next[0]), true, nil
+		default:
+			return append(result, "-"+current), false, nil
+		}
+	}
+	return result, false, nil
+}
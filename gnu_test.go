@@ -0,0 +1,98 @@
+package goflags
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGNUModeArgs(t *testing.T) {
+	flagSet := NewFlagSet()
+	flagSet.GNUMode = true
+	flagSet.SetConfigFilePath(filepath.Join(t.TempDir(), "config.yaml"))
+	var silent, verbose bool
+	var concurrency int
+	var list string
+	flagSet.BoolVarP(&silent, "silent", "s", false, "silent output")
+	flagSet.BoolVarP(&verbose, "verbose", "v", false, "verbose output")
+	flagSet.IntVarP(&concurrency, "concurrency", "c", 25, "concurrent requests")
+	flagSet.StringVarP(&list, "list", "l", "", "list of targets")
+
+	tests := []struct {
+		args     []string
+		expected []string
+	}{
+		{[]string{"-sv"}, []string{"-s", "-v"}},
+		{[]string{"-c10"}, []string{"-c", "10"}},
+		{[]string{"-svc", "10"}, []string{"-s", "-v", "-c", "10"}},
+		{[]string{"-svc10"}, []string{"-s", "-v", "-c", "10"}},
+		{[]string{"-c=10", "-s=false"}, []string{"-c", "10", "-s=false"}},
+		{[]string{"--list", "hosts.txt", "--concurrency=5"}, []string{"--list", "hosts.txt", "--concurrency=5"}},
+		{[]string{"-l", "hosts.txt", "target.com"}, []string{"-l", "hosts.txt", "target.com"}},
+		{[]string{"-h"}, []string{"-h"}},
+	}
+	for _, test := range tests {
+		args, err := flagSet.preprocessArgs(test.args)
+		require.Nil(t, err, "could not preprocess %v", test.args)
+		require.Equal(t, test.expected, args)
+	}
+
+	require.Nil(t, flagSet.Parse("-svc10", "--list", "hosts.txt"))
+	require.True(t, silent)
+	require.True(t, verbose)
+	require.Equal(t, 10, concurrency)
+	require.Equal(t, "hosts.txt", list)
+
+	tearDown(t.Name())
+}
+
+func TestGNUModeErrors(t *testing.T) {
+	flagSet := NewFlagSet()
+	flagSet.GNUMode = true
+	var silent, verbose bool
+	flagSet.BoolVarP(&silent, "silent", "s", false, "silent output")
+	flagSet.BoolVarP(&verbose, "verbose", "v", false, "verbose output")
+
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"-silent"}, "flag provided but not defined: -i in -silent\ndid you mean --silent?"},
+		{[]string{"-sx"}, "flag provided but not defined: -x in -sx"},
+		{[]string{"--s"}, "flag provided but not defined: --s\ndid you mean -s?"},
+		{[]string{"-x"}, "flag provided but not defined: -x"},
+		{[]string{"--slient"}, "flag provided but not defined: --slient\ndid you mean --silent?"},
+	}
+	for _, test := range tests {
+		_, err := flagSet.preprocessArgs(test.args)
+		require.EqualError(t, err, test.expected)
+	}
+
+	require.PanicsWithError(t, "short name -rl of flag --rate-limit must be a single character in GNU mode", func() {
+		var rateLimit int
+		flagSet.IntVarP(&rateLimit, "rate-limit", "rl", 150, "requests per second")
+	})
+
+	tearDown(t.Name())
+}
+
+func TestGNUModeUsage(t *testing.T) {
+	flagSet := NewFlagSet()
+	flagSet.GNUMode = true
+	var silent bool
+	var concurrency int
+	flagSet.BoolVarP(&silent, "silent", "s", false, "silent output")
+	flagSet.IntVarP(&concurrency, "concurrency", "c", 25, "concurrent requests")
+
+	output := &bytes.Buffer{}
+	flagSet.CommandLine.SetOutput(output)
+	os.Args = []string{os.Args[0], "-h"}
+	flagSet.usageFunc()
+	require.Contains(t, output.String(), "   -s, --silent           silent output\n")
+	require.Contains(t, output.String(), "   -c, --concurrency int  concurrent requests (default 25)\n")
+
+	tearDown(t.Name())
+}
@@ -3,6 +3,7 @@ package goflags
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
@@ -19,7 +20,6 @@ import (
 	fileutil "github.com/projectdiscovery/utils/file"
 	folderutil "github.com/projectdiscovery/utils/folder"
 	permissionutil "github.com/projectdiscovery/utils/permission"
-	"golang.org/x/exp/maps"
 	"gopkg.in/yaml.v3"
 )
 
@@ -44,6 +44,33 @@ type FlagSet struct {
 
 	// commonFlags holds reference to CommonFlags if AddCommonFlags was called
 	commonFlags *CommonFlags
+
+	// AllowPrefixMatching accepts unambiguous prefixes of long flag names, e.g. -rate for -rate-limit
+	AllowPrefixMatching bool
+
+	// Interspersed allows flags after positional arguments, e.g. tool target.com -silent.
+	// Arguments after -- are always positional.
+	Interspersed bool
+
+	// GNUMode enables GNU style parsing: short names are single characters which can be
+	// bundled (-sv) or given attached values (-c10), and long names require two dashes.
+	// It must be enabled before flags are registered.
+	GNUMode bool
+
+	// configSchemaURL is the JSON schema location referenced in the generated config file
+	configSchemaURL string
+
+	examples []example
+
+	// constraints are the rules between flags checked at the end of Parse
+	constraints []flagConstraint
+
+	// args are the named positional arguments, bound at the end of Parse
+	args      []*ArgData
+	argsRange *[2]int
+
+	// portServicesKey is the config file key holding user defined port services
+	portServicesKey string
 }
 
 type groupData struct {
@@ -59,6 +86,19 @@ type FlagData struct {
 	defaultValue interface{}
 	skipMarshal  bool
 	field        flag.Value
+	envName      string
+	completer    func(prefix string) []string `hash:"-"`
+	examples     []example                    `hash:"-"`
+	source       ValueSource                  `hash:"-"`
+	longHelp     string
+	hidden       bool
+	deprecated   string
+	required     bool
+	requires     []string
+	implies      []implication
+	validators   []Validator `hash:"-"`
+	negatable    bool
+	levels       []string
 }
 
 // Group sets the group for a flag data
@@ -118,7 +158,34 @@ func (flagSet *FlagSet) Parse(args ...string) error {
 	if len(args) > 0 {
 		toParse = args
 	}
-	_ = flagSet.CommandLine.Parse(toParse)
+	if len(toParse) > 0 && toParse[0] == completeCommand {
+		flagSet.writeCompletionCandidates(os.Stdout, toParse[1:])
+		os.Exit(0)
+	}
+	flagSet.checkFlagRules()
+	flagSet.registerNegations()
+	if configFilePath, err := flagSet.GetConfigFilePath(); err == nil && fileutil.FileExists(configFilePath) {
+		if err := flagSet.loadConfigFilePortServices(configFilePath); err != nil {
+			return err
+		}
+	}
+	toParse, err := flagSet.preprocessArgs(toParse)
+	if err != nil {
+		fmt.Fprintln(flagSet.CommandLine.Output(), err)
+		switch flagSet.CommandLine.ErrorHandling() {
+		case flag.ContinueOnError:
+			return err
+		case flag.PanicOnError:
+			panic(err)
+		}
+		os.Exit(2)
+	}
+	// the command line exits or panics on errors by itself for the other error handling modes
+	if err := flagSet.CommandLine.Parse(toParse); err != nil {
+		return err
+	}
+	flagSet.markCommandLineSources()
+	flagSet.warnDeprecatedFlags()
 	configFilePath, _ := flagSet.GetConfigFilePath()
 
 	// migrate data from old config dir to new one
@@ -134,15 +201,19 @@ func (flagSet *FlagSet) Parse(args ...string) error {
 		if !fileutil.FolderExists(configFileDir) {
 			_ = fileutil.CreateFolder(configFileDir)
 		}
-		return os.WriteFile(configFilePath, configData, permissionutil.ConfigFilePermission)
+		if err := os.WriteFile(configFilePath, configData, permissionutil.ConfigFilePermission); err != nil {
+			return err
+		}
+		return flagSet.validateFlags()
 	}
 
-	_ = flagSet.MergeConfigFile(configFilePath) // try to read default config after parsing flags
+	// try to read default config after parsing flags
+	configErr := flagSet.MergeConfigFile(configFilePath)
 
 	// Start common flags handlers if AddCommonFlags was called
 	flagSet.startCommonFlagsHandlers()
 
-	return nil
+	return errors.Join(configErr, flagSet.validateFlags())
 }
 
 // AttemptConfigMigration attempts to migrate config from old config dir to new one
@@ -168,6 +239,11 @@ func AttemptConfigMigration() {
 func (flagSet *FlagSet) generateDefaultConfig() []byte {
 	hashes := make(map[string]struct{})
 	configBuffer := &bytes.Buffer{}
+	if flagSet.configSchemaURL != "" {
+		configBuffer.WriteString("# yaml-language-server: $schema=")
+		configBuffer.WriteString(flagSet.configSchemaURL)
+		configBuffer.WriteString("\n")
+	}
 	configBuffer.WriteString("# ")
 	configBuffer.WriteString(path.Base(os.Args[0]))
 	configBuffer.WriteString(" config file\n# generated by https://github.com/projectdiscovery/goflags\n\n")
@@ -230,40 +306,43 @@ func (flagSet *FlagSet) CreateGroup(groupName, description string, flags ...*Fla
 //
 // Command line flags however always take precedence over config file ones.
 func (flagSet *FlagSet) readConfigFile(filePath string) error {
-	if empty, err := fileutil.IsEmpty(filePath); err == nil && empty {
-		return nil
-	}
-
-	if isCommentOnly(filePath) {
-		return nil
-	}
-
-	file, err := os.Open(filePath)
-	if err != nil {
+	data, err := readConfigData(filePath)
+	if err != nil || data == nil {
 		return err
 	}
-	defer file.Close()
-
-	data := make(map[string]interface{})
-	err = yaml.NewDecoder(file).Decode(&data)
-	if err != nil {
-		return err
+	var errs []error
+	if err := flagSet.registerConfigPortServices(data); err != nil {
+		errs = append(errs, err)
 	}
 	flagSet.CommandLine.VisitAll(func(fl *flag.Flag) {
 		item, ok := data[fl.Name]
 		value := fl.Value.String()
 
+		flagData := flagSet.flagKeys.values[fl.Name]
+		if flagData != nil && flagData.source == SourceCLI {
+			return
+		}
+		if _, ok := fl.Value.(*negatedBool); ok {
+			return
+		}
 		if strings.EqualFold(fl.DefValue, value) && ok {
+			if flagData != nil {
+				flagData.source = SourceConfig
+			}
 			switch itemValue := item.(type) {
 			case string:
 				_ = fl.Value.Set(itemValue)
 			case bool:
 				_ = fl.Value.Set(strconv.FormatBool(itemValue))
-		case int:
-			_ = fl.Value.Set(strconv.Itoa(itemValue))
-		case int64:
-			_ = fl.Value.Set(strconv.FormatInt(itemValue, 10))
-		case time.Duration:
+			case int:
+				errs = appendConfigNumberError(errs, fl, strconv.Itoa(itemValue))
+			case int64:
+				errs = appendConfigNumberError(errs, fl, strconv.FormatInt(itemValue, 10))
+			case uint64:
+				errs = appendConfigNumberError(errs, fl, strconv.FormatUint(itemValue, 10))
+			case float64:
+				errs = appendConfigNumberError(errs, fl, strconv.FormatFloat(itemValue, 'f', -1, 64))
+			case time.Duration:
 				_ = fl.Value.Set(itemValue.String())
 			case []interface{}:
 				for _, v := range itemValue {
@@ -279,6 +358,7 @@ func (flagSet *FlagSet) readConfigFile(filePath string) error {
 	flagSet.configOnlyKeys.forEach(func(key string, flagData *FlagData) {
 		item, ok := data[key]
 		if ok {
+			flagData.source = SourceConfig
 			fl := flag.Lookup(key)
 			if fl == nil {
 				flag.Var(flagData.field, key, flagData.usage)
@@ -290,11 +370,15 @@ func (flagSet *FlagSet) readConfigFile(filePath string) error {
 				_ = fl.Value.Set(data)
 			case bool:
 				_ = fl.Value.Set(strconv.FormatBool(data))
-		case int:
-			_ = fl.Value.Set(strconv.Itoa(data))
-		case int64:
-			_ = fl.Value.Set(strconv.FormatInt(data, 10))
-		case []interface{}:
+			case int:
+				_ = fl.Value.Set(strconv.Itoa(data))
+			case int64:
+				_ = fl.Value.Set(strconv.FormatInt(data, 10))
+			case uint64:
+				_ = fl.Value.Set(strconv.FormatUint(data, 10))
+			case float64:
+				_ = fl.Value.Set(strconv.FormatFloat(data, 'f', -1, 64))
+			case []interface{}:
 				for _, v := range data {
 					vStr, ok := v.(string)
 					if ok {
@@ -304,7 +388,39 @@ func (flagSet *FlagSet) readConfigFile(filePath string) error {
 			}
 		}
 	})
-	return nil
+	return errors.Join(errs...)
+}
+
+// readConfigData decodes the items of a config file, nil for empty or comment only files
+func readConfigData(filePath string) (map[string]interface{}, error) {
+	if empty, err := fileutil.IsEmpty(filePath); err == nil && empty {
+		return nil, nil
+	}
+
+	if isCommentOnly(filePath) {
+		return nil, nil
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	data := make(map[string]interface{})
+	if err := yaml.NewDecoder(file).Decode(&data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// appendConfigNumberError sets a numeric value from the config file on a flag,
+// appending an error if the value does not fit the type of the flag
+func appendConfigNumberError(errs []error, fl *flag.Flag, value string) []error {
+	if err := fl.Value.Set(value); err != nil {
+		errs = append(errs, fmt.Errorf("invalid value %s for flag -%s in config: %v", value, fl.Name, err))
+	}
+	return errs
 }
 
 // TODO: move to fileutil
@@ -335,7 +451,7 @@ func (flagSet *FlagSet) VarP(field flag.Value, long, short, usage string) *FlagD
 	if short != "" {
 		flagData.short = short
 		flagSet.CommandLine.Var(field, short, usage)
-		flagSet.flagKeys.Set(short, flagData)
+		flagSet.setShortFlag(short, flagData)
 	}
 	flagSet.CommandLine.Var(field, long, usage)
 	flagSet.flagKeys.Set(long, flagData)
@@ -350,10 +466,18 @@ func (flagSet *FlagSet) Var(field flag.Value, long, usage string) *FlagData {
 // StringVarEnv adds a string flag with a shortname and longname with a default value read from env variable
 // with a default value fallback
 func (flagSet *FlagSet) StringVarEnv(field *string, long, short, defaultValue, envName, usage string) *FlagData {
-	if envValue, exists := os.LookupEnv(envName); exists {
-		defaultValue = envValue
+	value := defaultValue
+	envValue, exists := os.LookupEnv(envName)
+	if exists {
+		value = envValue
+	}
+	flagData := flagSet.StringVarP(field, long, short, value, usage)
+	flagData.defaultValue = defaultValue
+	flagData.envName = envName
+	if exists {
+		flagData.source = SourceEnv
 	}
-	return flagSet.StringVarP(field, long, short, defaultValue, usage)
+	return flagData
 }
 
 // StringVarP adds a string flag with a shortname and longname
@@ -366,7 +490,7 @@ func (flagSet *FlagSet) StringVarP(field *string, long, short, defaultValue, usa
 	if short != "" {
 		flagData.short = short
 		flagSet.CommandLine.StringVar(field, short, defaultValue, usage)
-		flagSet.flagKeys.Set(short, flagData)
+		flagSet.setShortFlag(short, flagData)
 	}
 	flagSet.CommandLine.StringVar(field, long, defaultValue, usage)
 	flagSet.flagKeys.Set(long, flagData)
@@ -388,7 +512,7 @@ func (flagSet *FlagSet) BoolVarP(field *bool, long, short string, defaultValue b
 	if short != "" {
 		flagData.short = short
 		flagSet.CommandLine.BoolVar(field, short, defaultValue, usage)
-		flagSet.flagKeys.Set(short, flagData)
+		flagSet.setShortFlag(short, flagData)
 	}
 	flagSet.CommandLine.BoolVar(field, long, defaultValue, usage)
 	flagSet.flagKeys.Set(long, flagData)
@@ -411,7 +535,7 @@ func (flagSet *FlagSet) IntVarP(field *int, long, short string, defaultValue int
 	if short != "" {
 		flagData.short = short
 		flagSet.CommandLine.IntVar(field, short, defaultValue, usage)
-		flagSet.flagKeys.Set(short, flagData)
+		flagSet.setShortFlag(short, flagData)
 	}
 	flagSet.CommandLine.IntVar(field, long, defaultValue, usage)
 	flagSet.flagKeys.Set(long, flagData)
@@ -434,7 +558,7 @@ func (flagSet *FlagSet) Int64VarP(field *int64, long, short string, defaultValue
 	if short != "" {
 		flagData.short = short
 		flagSet.CommandLine.Int64Var(field, short, defaultValue, usage)
-		flagSet.flagKeys.Set(short, flagData)
+		flagSet.setShortFlag(short, flagData)
 	}
 	flagSet.CommandLine.Int64Var(field, long, defaultValue, usage)
 	flagSet.flagKeys.Set(long, flagData)
@@ -446,6 +570,75 @@ func (flagSet *FlagSet) Int64Var(field *int64, long string, defaultValue int64,
 	return flagSet.Int64VarP(field, long, "", defaultValue, usage)
 }
 
+// UintVarP adds a uint flag with a shortname and longname
+func (flagSet *FlagSet) UintVarP(field *uint, long, short string, defaultValue uint, usage string) *FlagData {
+	flagData := &FlagData{
+		usage:        usage,
+		short:        short,
+		long:         long,
+		defaultValue: strconv.FormatUint(uint64(defaultValue), 10),
+	}
+	if short != "" {
+		flagData.short = short
+		flagSet.CommandLine.UintVar(field, short, defaultValue, usage)
+		flagSet.setShortFlag(short, flagData)
+	}
+	flagSet.CommandLine.UintVar(field, long, defaultValue, usage)
+	flagSet.flagKeys.Set(long, flagData)
+	return flagData
+}
+
+// UintVar adds a uint flag with a longname
+func (flagSet *FlagSet) UintVar(field *uint, long string, defaultValue uint, usage string) *FlagData {
+	return flagSet.UintVarP(field, long, "", defaultValue, usage)
+}
+
+// Uint64VarP adds a uint64 flag with a shortname and longname
+func (flagSet *FlagSet) Uint64VarP(field *uint64, long, short string, defaultValue uint64, usage string) *FlagData {
+	flagData := &FlagData{
+		usage:        usage,
+		short:        short,
+		long:         long,
+		defaultValue: strconv.FormatUint(defaultValue, 10),
+	}
+	if short != "" {
+		flagData.short = short
+		flagSet.CommandLine.Uint64Var(field, short, defaultValue, usage)
+		flagSet.setShortFlag(short, flagData)
+	}
+	flagSet.CommandLine.Uint64Var(field, long, defaultValue, usage)
+	flagSet.flagKeys.Set(long, flagData)
+	return flagData
+}
+
+// Uint64Var adds a uint64 flag with a longname
+func (flagSet *FlagSet) Uint64Var(field *uint64, long string, defaultValue uint64, usage string) *FlagData {
+	return flagSet.Uint64VarP(field, long, "", defaultValue, usage)
+}
+
+// Float64VarP adds a float64 flag with a shortname and longname
+func (flagSet *FlagSet) Float64VarP(field *float64, long, short string, defaultValue float64, usage string) *FlagData {
+	flagData := &FlagData{
+		usage:        usage,
+		short:        short,
+		long:         long,
+		defaultValue: strconv.FormatFloat(defaultValue, 'g', -1, 64),
+	}
+	if short != "" {
+		flagData.short = short
+		flagSet.CommandLine.Float64Var(field, short, defaultValue, usage)
+		flagSet.setShortFlag(short, flagData)
+	}
+	flagSet.CommandLine.Float64Var(field, long, defaultValue, usage)
+	flagSet.flagKeys.Set(long, flagData)
+	return flagData
+}
+
+// Float64Var adds a float64 flag with a longname
+func (flagSet *FlagSet) Float64Var(field *float64, long string, defaultValue float64, usage string) *FlagData {
+	return flagSet.Float64VarP(field, long, "", defaultValue, usage)
+}
+
 // StringSliceVarP adds a string slice flag with a shortname and longname
 // Use options to customize the behavior
 func (flagSet *FlagSet) StringSliceVarP(field *StringSlice, long, short string, defaultValue StringSlice, usage string, options Options) *FlagData {
@@ -465,7 +658,7 @@ func (flagSet *FlagSet) StringSliceVarP(field *StringSlice, long, short string,
 	if short != "" {
 		flagData.short = short
 		flagSet.CommandLine.Var(field, short, usage)
-		flagSet.flagKeys.Set(short, flagData)
+		flagSet.setShortFlag(short, flagData)
 	}
 	flagSet.CommandLine.Var(field, long, usage)
 	flagSet.flagKeys.Set(long, flagData)
@@ -516,7 +709,7 @@ func (flagSet *FlagSet) RuntimeMapVarP(field *RuntimeMap, long, short string, de
 	if short != "" {
 		flagData.short = short
 		flagSet.CommandLine.Var(field, short, usage)
-		flagSet.flagKeys.Set(short, flagData)
+		flagSet.setShortFlag(short, flagData)
 	}
 	flagSet.CommandLine.Var(field, long, usage)
 	flagSet.flagKeys.Set(long, flagData)
@@ -531,9 +724,13 @@ func (flagSet *FlagSet) PortVar(field *Port, long string, defaultValue []string,
 // PortVarP adds a port flag with a shortname and longname
 func (flagSet *FlagSet) PortVarP(field *Port, long, short string, defaultValue []string, usage string) *FlagData {
 	for _, item := range defaultValue {
-		_ = field.Set(item)
+		if err := field.Set(item); err != nil {
+			panic(fmt.Errorf("invalid default value %q for flag -%v: %w", item, long, err))
+		}
 	}
-	portOptionDefaultValues[field] = maps.Clone(field.kv)
+	// exclusions of the default value only apply to the default ports
+	field.excluded = nil
+	field.isDefault = true
 
 	flagData := &FlagData{
 		usage:        usage,
@@ -545,7 +742,7 @@ func (flagSet *FlagSet) PortVarP(field *Port, long, short string, defaultValue [
 	if short != "" {
 		flagData.short = short
 		flagSet.CommandLine.Var(field, short, usage)
-		flagSet.flagKeys.Set(short, flagData)
+		flagSet.setShortFlag(short, flagData)
 	}
 	flagSet.CommandLine.Var(field, long, usage)
 	flagSet.flagKeys.Set(long, flagData)
@@ -577,7 +774,7 @@ func (flagSet *FlagSet) EnumVarP(field *string, long, short string, defaultValue
 	if short != "" {
 		flagData.short = short
 		flagSet.CommandLine.Var(&EnumVar{allowedTypes, field}, short, usage)
-		flagSet.flagKeys.Set(short, flagData)
+		flagSet.setShortFlag(short, flagData)
 	}
 	flagSet.CommandLine.Var(&EnumVar{allowedTypes, field}, long, usage)
 	flagSet.flagKeys.Set(long, flagData)
@@ -612,7 +809,7 @@ func (flagSet *FlagSet) EnumSliceVarP(field *[]string, long, short string, defau
 	if short != "" {
 		flagData.short = short
 		flagSet.CommandLine.Var(&EnumSliceVar{allowedTypes, field}, short, usage)
-		flagSet.flagKeys.Set(short, flagData)
+		flagSet.setShortFlag(short, flagData)
 	}
 	flagSet.CommandLine.Var(&EnumSliceVar{allowedTypes, field}, long, usage)
 	flagSet.flagKeys.Set(long, flagData)
@@ -634,35 +831,95 @@ func (flagSet *FlagSet) usageFunc() {
 	}
 
 	cliOutput := flagSet.CommandLine.Output()
-	fmt.Fprintf(cliOutput, "%s\n\n", flagSet.description)
-	fmt.Fprintf(cliOutput, "Usage:\n  %s [flags]\n\n", os.Args[0])
-	fmt.Fprintf(cliOutput, "Flags:\n")
-
 	writer := tabwriter.NewWriter(cliOutput, 0, 0, 1, ' ', 0)
 
-	// If a user has specified a group with help, and we have groups, return with the tool's usage function
+	// If a user has asked for json help, print the manifest of the flags
+	if flagSet.isJSONHelp(os.Args) {
+		_ = flagSet.WriteManifest(cliOutput)
+		return
+	}
+
+	// If a user has specified a flag with help, display the detailed help page of the flag
+	if len(os.Args) == 3 && flagSet.getGroupbyName(strings.ToLower(os.Args[2])).name == "" {
+		flag := flagSet.getFlagByName(os.Args[2])
+		if flag != nil {
+			flagSet.displaySingleFlagUsageFunc(os.Args[2], flag, cliOutput, writer)
+			return
+		}
+	}
+
+	// If a user has specified a group with help, and we have groups, display only the flags of the group
 	if len(flagSet.groups) > 0 && len(os.Args) == 3 {
 		group := flagSet.getGroupbyName(strings.ToLower(os.Args[2]))
 		if group.name != "" {
-			flagSet.displayGroupUsageFunc(newUniqueDeduper(), group, cliOutput, writer)
+			flagSet.writeUsageHeader(cliOutput, os.Args[0])
+			for _, current := range flagSet.groupedFlags() {
+				if current.name == group.name {
+					flagSet.writeFlagGroup(cliOutput, current)
+				}
+			}
 			return
 		}
-		flag := flagSet.getFlagByName(os.Args[2])
-		if flag != nil {
-			flagSet.displaySingleFlagUsageFunc(os.Args[2], flag, cliOutput, writer)
-			return
+	}
+
+	flagSet.writeUsage(cliOutput, os.Args[0])
+}
+
+// writeUsageHeader writes the description and usage line preceding the flags in the help
+func (flagSet *FlagSet) writeUsageHeader(w io.Writer, toolName string) {
+	if !isEmpty(flagSet.description) {
+		fmt.Fprintf(w, "%s\n\n", flagSet.description)
+	}
+	flagSet.writeUsageLine(w, toolName)
+	fmt.Fprintf(w, "Flags:\n")
+}
+
+// writeUsage writes the help output of the flagset, which is also the markdown usage
+func (flagSet *FlagSet) writeUsage(w io.Writer, toolName string) {
+	flagSet.writeUsageHeader(w, toolName)
+
+	// named groups are followed by a blank line, a flat flag list isn't
+	needsSeparator := false
+	for _, group := range flagSet.groupedFlags() {
+		if len(group.flags) == 0 {
+			continue
 		}
+		flagSet.writeFlagGroup(w, group)
+		needsSeparator = group.name == ""
 	}
 
-	if len(flagSet.groups) > 0 {
-		flagSet.usageFuncForGroups(cliOutput, writer)
-	} else {
-		flagSet.usageFuncInternal(writer)
+	if examples := flagSet.allExamples(); len(examples) > 0 {
+		if needsSeparator {
+			fmt.Fprintf(w, "\n")
+		}
+		writeExamples(w, examples)
+		needsSeparator = true
 	}
 
 	// If there is a custom help text specified, print it
 	if !isEmpty(flagSet.customHelpText) {
-		fmt.Fprintf(cliOutput, "\n%s\n", flagSet.customHelpText)
+		if needsSeparator {
+			fmt.Fprintf(w, "\n")
+		}
+		fmt.Fprintf(w, "%s\n", flagSet.customHelpText)
+	}
+}
+
+// writeFlagGroup writes the usage of the flags of a group, with the header and
+// trailing blank line of named groups
+func (flagSet *FlagSet) writeFlagGroup(w io.Writer, group flagGroup) {
+	if group.description != "" {
+		fmt.Fprintf(w, "%s:\n", normalizeGroupDescription(group.description))
+	}
+	writer := tabwriter.NewWriter(w, 0, 0, 1, ' ', 0)
+	for _, data := range group.flags {
+		if currentFlag := flagSet.lookupFlag(data); currentFlag != nil {
+			fmt.Fprint(writer, flagSet.createUsageString(data, currentFlag), "\n")
+		}
+	}
+	writer.Flush()
+	if group.name != "" {
+		fmt.Fprintf(w, "\n")
 	}
 }
 
@@ -691,79 +948,58 @@ func (flagSet *FlagSet) getFlagByName(name string) *FlagData {
 	return flagData
 }
 
-// usageFuncInternal prints usage for command line flags
-func (flagSet *FlagSet) usageFuncInternal(writer *tabwriter.Writer) {
-	uniqueDeduper := newUniqueDeduper()
-
-	flagSet.flagKeys.forEach(func(key string, data *FlagData) {
-		if currentFlag := flagSet.CommandLine.Lookup(key); currentFlag != nil {
-			if !uniqueDeduper.isUnique(data) {
-				return
-			}
-			result := createUsageString(data, currentFlag)
-			fmt.Fprint(writer, result, "\n")
-		}
-	})
-	writer.Flush()
+// flagGroup is a group of flags in the order they are displayed in usage
+type flagGroup struct {
+	name        string
+	description string
+	flags       []*FlagData
 }
 
-// usageFuncForGroups prints usage for command line flags with grouping enabled
-func (flagSet *FlagSet) usageFuncForGroups(cliOutput io.Writer, writer *tabwriter.Writer) {
+// groupedFlags returns the unique command line flags of the flagset organised
+// the same way as the usage output. When no groups are defined, all flags are
+// returned in a single unnamed group.
+func (flagSet *FlagSet) groupedFlags() []flagGroup {
 	uniqueDeduper := newUniqueDeduper()
 
-	var otherOptions []string
-	for _, group := range flagSet.groups {
-		otherOptions = append(otherOptions, flagSet.displayGroupUsageFunc(uniqueDeduper, group, cliOutput, writer)...)
-	}
-
-	// Print Any additional flag that may have been left
-	if len(otherOptions) > 0 {
-		fmt.Fprintf(cliOutput, "%s:\n", normalizeGroupDescription(flagSet.OtherOptionsGroupName))
-
-		for _, option := range otherOptions {
-			fmt.Fprint(writer, option, "\n")
-		}
-		writer.Flush()
+	if len(flagSet.groups) == 0 {
+		all := flagGroup{}
+		flagSet.flagKeys.forEach(func(key string, data *FlagData) {
+			if flagSet.CommandLine.Lookup(key) != nil && !data.hidden && uniqueDeduper.isUnique(data) {
+				all.flags = append(all.flags, data)
+			}
+		})
+		return []flagGroup{all}
 	}
-}
-
-// displayGroupUsageFunc displays usage for a group
-func (flagSet *FlagSet) displayGroupUsageFunc(uniqueDeduper *uniqueDeduper, group groupData, cliOutput io.Writer, writer *tabwriter.Writer) []string {
-	fmt.Fprintf(cliOutput, "%s:\n", normalizeGroupDescription(group.description))
 
-	var otherOptions []string
-	flagSet.flagKeys.forEach(func(key string, data *FlagData) {
-		if currentFlag := flagSet.CommandLine.Lookup(key); currentFlag != nil {
-			if data.group == "" {
-				if !uniqueDeduper.isUnique(data) {
-					return
-				}
-				otherOptions = append(otherOptions, createUsageString(data, currentFlag))
-				return
-			}
-			// Ignore the flag if it's not in our intended group
-			if !strings.EqualFold(data.group, group.name) {
+	var groups []flagGroup
+	for _, group := range flagSet.groups {
+		current := flagGroup{name: group.name, description: group.description}
+		flagSet.flagKeys.forEach(func(key string, data *FlagData) {
+			if flagSet.CommandLine.Lookup(key) == nil || data.hidden || !strings.EqualFold(data.group, group.name) {
 				return
 			}
-			if !uniqueDeduper.isUnique(data) {
-				return
+			if uniqueDeduper.isUnique(data) {
+				current.flags = append(current.flags, data)
 			}
-			result := createUsageString(data, currentFlag)
-			fmt.Fprint(writer, result, "\n")
+		})
+		groups = append(groups, current)
+	}
+
+	other := flagGroup{description: flagSet.OtherOptionsGroupName}
+	flagSet.flagKeys.forEach(func(key string, data *FlagData) {
+		if flagSet.CommandLine.Lookup(key) != nil && !data.hidden && data.group == "" && uniqueDeduper.isUnique(data) {
+			other.flags = append(other.flags, data)
 		}
 	})
-	writer.Flush()
-	fmt.Printf("\n")
-	return otherOptions
+	if len(other.flags) > 0 {
+		groups = append(groups, other)
+	}
+	return groups
 }
 
-// displaySingleFlagUsageFunc displays usage for a single flag
-func (flagSet *FlagSet) displaySingleFlagUsageFunc(name string, data *FlagData, _ io.Writer, writer *tabwriter.Writer) {
-	if currentFlag := flagSet.CommandLine.Lookup(name); currentFlag != nil {
-		result := createUsageString(data, currentFlag)
-		fmt.Fprint(writer, result, "\n")
-		writer.Flush()
-	}
+// lookupFlag returns the command line flag registered for a flag data
+func (flagSet *FlagSet) lookupFlag(data *FlagData) *flag.Flag {
+	return flagSet.CommandLine.Lookup(primaryName(data))
 }
 
 type uniqueDeduper struct {
@@ -784,51 +1020,61 @@ func (u *uniqueDeduper) isUnique(data *FlagData) bool {
 	return true
 }
 
-func createUsageString(data *FlagData, currentFlag *flag.Flag) string {
+func (flagSet *FlagSet) createUsageString(data *FlagData, currentFlag *flag.Flag) string {
 	valueType := reflect.TypeOf(currentFlag.Value)
 
-	result := createUsageFlagNames(data)
+	result := flagSet.createUsageFlagNames(data)
 	result += createUsageTypeAndDescription(currentFlag, valueType)
 	result += createUsageDefaultValue(data, currentFlag, valueType)
+	if len(data.levels) > 0 {
+		result += " (levels: " + levelsUsage(data) + ")"
+	}
+	if data.required {
+		result += " (required)"
+	}
+	if data.deprecated != "" {
+		result += " (deprecated: " + data.deprecated + ")"
+	}
 
 	return result
 }
 
 func createUsageDefaultValue(data *FlagData, currentFlag *flag.Flag, valueType reflect.Type) string {
-	if !isZeroValue(currentFlag, currentFlag.DefValue) {
-		defaultValueTemplate := " (default "
-		switch valueType.String() { // ugly hack because "flag.stringValue" is not exported from the parent library
-		case "*flag.stringValue":
-			defaultValueTemplate += "%q"
-		default:
-			defaultValueTemplate += "%v"
-		}
-		defaultValueTemplate += ")"
-		return fmt.Sprintf(defaultValueTemplate, data.defaultValue)
+	if defaultValue := usageDefaultValue(data, currentFlag, valueType); defaultValue != "" {
+		return " (default " + defaultValue + ")"
 	}
 	return ""
 }
 
+// usageDefaultValue returns the default value of a flag formatted for display.
+// An empty string is returned when the default is the zero value of the flag type.
+func usageDefaultValue(data *FlagData, currentFlag *flag.Flag, valueType reflect.Type) string {
+	if isZeroValue(currentFlag, declaredDefault(data, currentFlag)) {
+		return ""
+	}
+	switch valueType.String() { // ugly hack because "flag.stringValue" is not exported from the parent library
+	case "*flag.stringValue":
+		return fmt.Sprintf("%q", data.defaultValue)
+	default:
+		return fmt.Sprintf("%v", data.defaultValue)
+	}
+}
+
+// declaredDefault returns the default value given when the flag was defined.
+// Unlike the DefValue of the flag, it is not replaced by the value of the
+// environment variable of the flag.
+func declaredDefault(data *FlagData, currentFlag *flag.Flag) string {
+	if value, ok := data.defaultValue.(string); ok {
+		return value
+	}
+	return currentFlag.DefValue
+}
+
 func createUsageTypeAndDescription(currentFlag *flag.Flag, valueType reflect.Type) string {
 	var result string
 
-	flagDisplayType, usage := flag.UnquoteUsage(currentFlag)
-	if len(flagDisplayType) > 0 {
-		if flagDisplayType == "value" { // hardcoded in the goflags library
-			switch valueType.Kind() {
-			case reflect.Ptr:
-				pointerTypeElement := valueType.Elem()
-				switch pointerTypeElement.Kind() {
-				case reflect.Slice, reflect.Array:
-					switch pointerTypeElement.Elem().Kind() {
-					case reflect.String:
-						flagDisplayType = "string[]"
-					default:
-						flagDisplayType = "value[]"
-					}
-				}
-			}
-		}
+	_, usage := flag.UnquoteUsage(currentFlag)
+	if flagDisplayType := usageFlagType(currentFlag, valueType); len(flagDisplayType) > 0 {
 		result += " " + flagDisplayType
 	}
 
@@ -837,18 +1083,35 @@ func createUsageTypeAndDescription(currentFlag *flag.Flag, valueType reflect.Typ
 	return result
 }
 
-func createUsageFlagNames(data *FlagData) string {
-	flagNames := strings.Repeat(" ", 2) + "\t"
-
-	var validFlags []string
-	addValidParam := func(value string) {
-		if !isEmpty(value) {
-			validFlags = append(validFlags, fmt.Sprintf("-%s", value))
+// usageFlagType returns the value type of a flag as displayed in usage, e.g. "string[]".
+// An empty string is returned for boolean flags.
+func usageFlagType(currentFlag *flag.Flag, valueType reflect.Type) string {
+	flagDisplayType, _ := flag.UnquoteUsage(currentFlag)
+	if flagDisplayType == "value" { // hardcoded in the goflags library
+		if _, ok := currentFlag.Value.(*float32Value); ok {
+			return "float"
+		}
+		switch valueType.Kind() {
+		case reflect.Ptr:
+			pointerTypeElement := valueType.Elem()
+			switch pointerTypeElement.Kind() {
+			case reflect.Slice, reflect.Array:
+				switch pointerTypeElement.Elem().Kind() {
+				case reflect.String:
+					flagDisplayType = "string[]"
+				default:
+					flagDisplayType = "value[]"
+				}
+			}
 		}
 	}
+	return flagDisplayType
+}
+
+func (flagSet *FlagSet) createUsageFlagNames(data *FlagData) string {
+	flagNames := strings.Repeat(" ", 2) + "\t"
 
-	addValidParam(data.short)
-	addValidParam(data.long)
+	validFlags := flagSet.displayNames(data)
 
 	if len(validFlags) == 0 {
 		panic("CLI arguments cannot be empty.")
@@ -0,0 +1,166 @@
+package goflags
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const (
+	jsonSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+	sizePattern      = `^[0-9]+([kKmMgGtT][bB])?$`
+	durationPattern  = `^([0-9]+[dD]?|([0-9]*\.?[0-9]+([nN][sS]|[uU][sS]|µs|[mM][sS]|[sS]|[mM]|[hH]))+)$`
+	rateLimitPattern = `^[^=]+=[0-9]+/[0-9]*([nN][sS]|[uU][sS]|µs|[mM][sS]|[sS]|[mM])$`
+)
+
+// jsonSchema is the subset of JSON Schema used to describe config files
+type jsonSchema struct {
+	Schema      string                 `json:"$schema,omitempty"`
+	Title       string                 `json:"title,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Type        interface{}            `json:"type,omitempty"`
+	Properties  map[string]*jsonSchema `json:"properties,omitempty"`
+	Items       *jsonSchema            `json:"items,omitempty"`
+	Enum        []string               `json:"enum,omitempty"`
+	Pattern     string                 `json:"pattern,omitempty"`
+	Minimum     *int                   `json:"minimum,omitempty"`
+	Default     interface{}            `json:"default,omitempty"`
+}
+
+// SetConfigSchemaURL sets the location of the JSON schema for the config file.
+//
+// When set, the generated default config file starts with a yaml-language-server
+// modeline pointing to the schema so editors can validate and complete the file.
+func (flagSet *FlagSet) SetConfigSchemaURL(url string) {
+	flagSet.configSchemaURL = url
+}
+
+// JSONSchema returns a JSON schema describing the config file of the flagset.
+//
+// Property types are derived from the type of each flag, enum flags list their
+// allowed values, size, duration and rate limit flags are validated with patterns
+// and the usage of each flag is used as its description.
+func (flagSet *FlagSet) JSONSchema() ([]byte, error) {
+	schema := &jsonSchema{
+		Schema:     jsonSchemaDraft,
+		Title:      fmt.Sprintf("%s config file", getToolName()),
+		Type:       "object",
+		Properties: make(map[string]*jsonSchema),
+	}
+	if !isEmpty(flagSet.description) {
+		schema.Description = flagSet.description
+	}
+
+	uniqueDeduper := newUniqueDeduper()
+	flagSet.flagKeys.forEach(func(key string, data *FlagData) {
+		if !uniqueDeduper.isUnique(data) {
+			return
+		}
+		name := data.long
+		if name == "" {
+			name = data.short
+		}
+
+		var property *jsonSchema
+		if _, ok := flagSet.configOnlyKeys.values[key]; ok {
+			property = stringArraySchema(&jsonSchema{Type: "string"})
+		} else if currentFlag := flagSet.lookupFlag(data); currentFlag != nil {
+			property = flagValueSchema(currentFlag)
+		}
+		if property == nil {
+			return
+		}
+		property.Description = data.usage
+		schema.Properties[name] = property
+	})
+	if flagSet.portServicesKey != "" {
+		schema.Properties[flagSet.portServicesKey] = &jsonSchema{
+			Type:        "object",
+			Description: "port services usable in port flags, mapping names to ports",
+		}
+	}
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// flagValueSchema returns the schema of the value of a command line flag,
+// or nil if the flag can't be set from the config file.
+func flagValueSchema(currentFlag *flag.Flag) *jsonSchema {
+	switch value := currentFlag.Value.(type) {
+	case *callBackVar:
+		return nil
+	case *StringSlice, *RuntimeMap:
+		return stringArraySchema(&jsonSchema{Type: "string"})
+	case *EnumVar:
+		return &jsonSchema{Type: "string", Enum: sortedAllowedTypes(value.allowedTypes), Default: currentFlag.DefValue}
+	case *EnumSliceVar:
+		return stringArraySchema(&jsonSchema{Type: "string", Enum: sortedAllowedTypes(value.allowedTypes)})
+	case *Size:
+		return &jsonSchema{Type: []string{"string", "integer"}, Pattern: sizePattern}
+	case *RateLimitMap:
+		return stringArraySchema(&jsonSchema{Type: "string", Pattern: rateLimitPattern})
+	case *Port:
+		return &jsonSchema{Type: []string{"string", "integer", "array"}}
+	case *dynamicFlag:
+		return &jsonSchema{Type: []string{"boolean", "string", "number", "array"}}
+	case *OptionalBool:
+		return &jsonSchema{Type: "boolean"}
+	case *countValue:
+		if len(value.data.levels) > 0 {
+			return &jsonSchema{Type: []string{"integer", "string"}}
+		}
+		return &jsonSchema{Type: "integer"}
+	case flag.Getter:
+		return getterSchema(currentFlag, value.Get())
+	}
+	return &jsonSchema{Type: "string"}
+}
+
+// getterSchema returns the schema of a flag based on the type of its value
+func getterSchema(currentFlag *flag.Flag, value interface{}) *jsonSchema {
+	var schema *jsonSchema
+	switch value.(type) {
+	case bool:
+		schema = &jsonSchema{Type: "boolean"}
+		if defaultValue, err := strconv.ParseBool(currentFlag.DefValue); err == nil && defaultValue {
+			schema.Default = defaultValue
+		}
+		return schema
+	case int, int64:
+		schema = &jsonSchema{Type: "integer"}
+		if defaultValue, err := strconv.ParseInt(currentFlag.DefValue, 10, 64); err == nil && defaultValue != 0 {
+			schema.Default = defaultValue
+		}
+		return schema
+	case uint, uint64:
+		minimum := 0
+		schema = &jsonSchema{Type: "integer", Minimum: &minimum}
+		if defaultValue, err := strconv.ParseUint(currentFlag.DefValue, 10, 64); err == nil && defaultValue != 0 {
+			schema.Default = defaultValue
+		}
+		return schema
+	case float32, float64:
+		schema = &jsonSchema{Type: "number"}
+		if defaultValue, err := strconv.ParseFloat(currentFlag.DefValue, 64); err == nil && defaultValue != 0 {
+			schema.Default = defaultValue
+		}
+		return schema
+	case time.Duration:
+		schema = &jsonSchema{Type: []string{"string", "integer"}, Pattern: durationPattern}
+	default:
+		schema = &jsonSchema{Type: "string"}
+	}
+	if !isZeroValue(currentFlag, currentFlag.DefValue) {
+		schema.Default = currentFlag.DefValue
+	}
+	return schema
+}
+
+// stringArraySchema returns the schema of a slice value, which can be
+// written either as a list or as a single value in the config file
+func stringArraySchema(items *jsonSchema) *jsonSchema {
+	return &jsonSchema{Type: []string{"array", "string"}, Items: items}
+}
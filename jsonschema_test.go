@@ -0,0 +1,101 @@
+package goflags
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONSchema(t *testing.T) {
+	flagSet := NewFlagSet()
+
+	var target, severity string
+	var templates StringSlice
+	var threads int
+	var silent bool
+	var timeout time.Duration
+	var maxSize Size
+	var rateLimits RateLimitMap
+	flagSet.StringVarP(&target, "target", "u", "", "target to scan")
+	flagSet.StringSliceVarP(&templates, "templates", "t", nil, "templates to run", CommaSeparatedStringSliceOptions)
+	flagSet.IntVarP(&threads, "threads", "c", 25, "number of threads")
+	flagSet.BoolVar(&silent, "silent", false, "silent output")
+	flagSet.DurationVar(&timeout, "timeout", 10*time.Second, "request timeout")
+	flagSet.SizeVar(&maxSize, "max-size", "", "max response size")
+	flagSet.RateLimitMapVar(&rateLimits, "rate-limits", nil, "rate limits per host", CommaSeparatedStringSliceOptions)
+	flagSet.EnumVar(&severity, "severity", EnumVariable(0), "severity to filter", AllowdTypes{"low": 0, "high": 1})
+	flagSet.CallbackVar(func() {}, "update", "update the tool")
+
+	data, err := flagSet.JSONSchema()
+	require.Nil(t, err)
+
+	var schema map[string]interface{}
+	require.Nil(t, json.Unmarshal(data, &schema))
+	require.Equal(t, jsonSchemaDraft, schema["$schema"])
+	require.Equal(t, "object", schema["type"])
+
+	properties := schema["properties"].(map[string]interface{})
+	require.NotContains(t, properties, "update", "callback flags can't be set from config")
+	require.NotContains(t, properties, "u", "short names should not be duplicated")
+
+	property := func(name string) map[string]interface{} {
+		require.Contains(t, properties, name)
+		return properties[name].(map[string]interface{})
+	}
+	require.Equal(t, "string", property("target")["type"])
+	require.Equal(t, "target to scan", property("target")["description"])
+	require.Equal(t, []interface{}{"array", "string"}, property("templates")["type"])
+	require.Equal(t, "integer", property("threads")["type"])
+	require.Equal(t, float64(25), property("threads")["default"])
+	require.Equal(t, "boolean", property("silent")["type"])
+	require.Equal(t, durationPattern, property("timeout")["pattern"])
+	require.Equal(t, sizePattern, property("max-size")["pattern"])
+	require.Equal(t, rateLimitPattern, property("rate-limits")["items"].(map[string]interface{})["pattern"])
+	require.Equal(t, []interface{}{"high", "low"}, property("severity")["enum"])
+	require.Equal(t, "low", property("severity")["default"])
+
+	tearDown(t.Name())
+}
+
+func TestJSONSchemaPatterns(t *testing.T) {
+	tests := []struct {
+		pattern string
+		valid   []string
+		invalid []string
+	}{
+		{pattern: sizePattern, valid: []string{"10", "2kb", "5MB", "1Gb"}, invalid: []string{"kb", "2 mb", "3pb"}},
+		{pattern: durationPattern, valid: []string{"10", "2d", "1h30m", "500ms", "1.5s"}, invalid: []string{"h", "1y", "ten"}},
+		{pattern: rateLimitPattern, valid: []string{"hackertarget=10/s", "host=5/30s", "host=1/m", "host=100/500ms"}, invalid: []string{"host=10", "=10/s", "host=a/s", "scanme.sh=2/d", "host=5/h"}},
+	}
+	for _, test := range tests {
+		re := regexp.MustCompile(test.pattern)
+		for _, value := range test.valid {
+			require.True(t, re.MatchString(value), "%q should match %s", value, test.pattern)
+		}
+		for _, value := range test.invalid {
+			require.False(t, re.MatchString(value), "%q should not match %s", value, test.pattern)
+		}
+	}
+
+	for _, value := range []string{"hackertarget=10/s", "host=1/m", "scanme.sh=2/d"} {
+		matches := regexp.MustCompile(rateLimitPattern).MatchString(value)
+		require.Equal(t, (&RateLimitMap{}).Set(value) == nil, matches, "rate limit pattern should match the values accepted by the flag for %q", value)
+	}
+}
+
+func TestGenerateDefaultConfigSchemaURL(t *testing.T) {
+	flagSet := NewFlagSet()
+	flagSet.SetConfigSchemaURL("https://example.com/config.schema.json")
+
+	var data string
+	flagSet.StringVar(&data, "test", "", "test flag")
+	defaultConfig := string(flagSet.generateDefaultConfig())
+
+	require.True(t, strings.HasPrefix(defaultConfig, "# yaml-language-server: $schema=https://example.com/config.schema.json\n"), "could not get schema modeline")
+
+	tearDown(t.Name())
+}
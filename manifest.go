@@ -0,0 +1,145 @@
+package goflags
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Manifest is a machine-readable description of the flags of a tool.
+//
+// It is meant for wrappers and orchestration that need to know the flags of a
+// tool without parsing its help output. The field names are part of the format
+// and stay stable between versions, so manifests can be compared.
+type Manifest struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Groups      []ManifestGroup `json:"groups,omitempty"`
+	Flags       []ManifestFlag  `json:"flags"`
+}
+
+// ManifestGroup is a group of flags in a manifest
+type ManifestGroup struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// ManifestFlag is a single flag in a manifest
+type ManifestFlag struct {
+	Long       string   `json:"long,omitempty"`
+	Short      string   `json:"short,omitempty"`
+	Type       string   `json:"type"`
+	Default    string   `json:"default,omitempty"`
+	Group      string   `json:"group,omitempty"`
+	Usage      string   `json:"usage"`
+	EnumValues []string `json:"enum_values,omitempty"`
+	Levels     []string `json:"levels,omitempty"`
+	Env        string   `json:"env,omitempty"`
+	Required   bool     `json:"required,omitempty"`
+	Negatable  bool     `json:"negatable,omitempty"`
+	Hidden     bool     `json:"hidden,omitempty"`
+	Deprecated string   `json:"deprecated,omitempty"`
+}
+
+// Hidden hides the flag from the help, completion and generated documentation.
+// The flag can still be used and is listed in the manifest.
+func (flagData *FlagData) Hidden() *FlagData {
+	flagData.hidden = true
+	return flagData
+}
+
+// Deprecated marks the flag as deprecated with a message pointing to its replacement.
+// A warning is printed when the flag is used on the command line.
+func (flagData *FlagData) Deprecated(message string) *FlagData {
+	flagData.deprecated = message
+	return flagData
+}
+
+// Manifest returns the manifest of the flags of the flagset
+func (flagSet *FlagSet) Manifest() *Manifest {
+	manifest := &Manifest{
+		Name:        getToolName(),
+		Description: flagSet.description,
+		Flags:       []ManifestFlag{},
+	}
+	for _, group := range flagSet.groups {
+		manifest.Groups = append(manifest.Groups, ManifestGroup{Name: group.name, Description: group.description})
+	}
+
+	uniqueDeduper := newUniqueDeduper()
+	flagSet.flagKeys.forEach(func(key string, data *FlagData) {
+		currentFlag := flagSet.CommandLine.Lookup(key)
+		if currentFlag == nil || !uniqueDeduper.isUnique(data) {
+			return
+		}
+		manifest.Flags = append(manifest.Flags, newManifestFlag(data, currentFlag))
+	})
+	return manifest
+}
+
+// WriteManifest writes the manifest of the flagset as indented JSON
+func (flagSet *FlagSet) WriteManifest(w io.Writer) error {
+	data, err := json.MarshalIndent(flagSet.Manifest(), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+// newManifestFlag creates the manifest entry of a flag
+func newManifestFlag(data *FlagData, currentFlag *flag.Flag) ManifestFlag {
+	manifestFlag := ManifestFlag{
+		Long:       data.long,
+		Short:      data.short,
+		Type:       flagKind(currentFlag.Value),
+		Group:      data.group,
+		Usage:      data.usage,
+		Env:        data.envName,
+		Required:   data.required,
+		Negatable:  data.negatable,
+		Hidden:     data.hidden,
+		Deprecated: data.deprecated,
+		Levels:     data.levels,
+	}
+	if _, isCallback := currentFlag.Value.(*callBackVar); !isCallback {
+		if defaultValue := declaredDefault(data, currentFlag); !isZeroValue(currentFlag, defaultValue) {
+			manifestFlag.Default = defaultValue
+		}
+	}
+	switch value := currentFlag.Value.(type) {
+	case *EnumVar:
+		manifestFlag.EnumValues = sortedAllowedTypes(value.allowedTypes)
+	case *EnumSliceVar:
+		manifestFlag.EnumValues = sortedAllowedTypes(value.allowedTypes)
+	}
+	return manifestFlag
+}
+
+// warnDeprecatedFlags prints a warning for every deprecated flag given on the command line
+func (flagSet *FlagSet) warnDeprecatedFlags() {
+	uniqueDeduper := newUniqueDeduper()
+	flagSet.flagKeys.forEach(func(key string, data *FlagData) {
+		if data.deprecated == "" || data.Source() != SourceCLI || !uniqueDeduper.isUnique(data) {
+			return
+		}
+		fmt.Fprintf(os.Stderr, "[WRN] flag %s is deprecated: %s\n", flagSet.joinFlagNames(data), data.deprecated)
+	})
+}
+
+// isJSONHelp returns true if the help was requested in json format (-h -json).
+// Tools defining their own -json flag keep the regular help for -h -json.
+func (flagSet *FlagSet) isJSONHelp(args []string) bool {
+	if flagSet.getFlagByName("json") != nil {
+		return false
+	}
+	for _, arg := range args[1:] {
+		if strings.HasPrefix(arg, "-") && strings.TrimLeft(arg, "-") == "json" {
+			return true
+		}
+	}
+	return false
+}
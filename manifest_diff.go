@@ -0,0 +1,184 @@
+package goflags
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// UpdateManifestEnv is the environment variable which makes CheckManifestCompatibility
+// write the current manifest to the golden file instead of comparing against it
+const UpdateManifestEnv = "GOFLAGS_UPDATE_MANIFEST"
+
+// ManifestChangeKind is the kind of a change between two manifests
+type ManifestChangeKind string
+
+const (
+	// FlagRemoved is used when a flag of the old manifest no longer exists
+	FlagRemoved ManifestChangeKind = "flag-removed"
+	// FlagAdded is used when a flag was added to the new manifest
+	FlagAdded ManifestChangeKind = "flag-added"
+	// ShortNameChanged is used when the short name of a flag was changed, added or removed
+	ShortNameChanged ManifestChangeKind = "short-name-changed"
+	// TypeChanged is used when the type of a flag was changed
+	TypeChanged ManifestChangeKind = "type-changed"
+	// DefaultChanged is used when the default value of a flag was changed
+	DefaultChanged ManifestChangeKind = "default-changed"
+)
+
+// ManifestChange is a single difference between two manifests
+type ManifestChange struct {
+	Kind     ManifestChangeKind
+	Flag     string
+	Old      string
+	New      string
+	Breaking bool
+}
+
+// String returns a readable description of the change
+func (change ManifestChange) String() string {
+	switch change.Kind {
+	case FlagRemoved:
+		return fmt.Sprintf("flag -%s was removed", change.Flag)
+	case FlagAdded:
+		return fmt.Sprintf("flag -%s was added", change.Flag)
+	default:
+		return fmt.Sprintf("%s of flag -%s changed from %q to %q", strings.TrimSuffix(string(change.Kind), "-changed"), change.Flag, change.Old, change.New)
+	}
+}
+
+// ManifestDiff is the list of changes between two manifests
+type ManifestDiff struct {
+	Changes []ManifestChange
+}
+
+// Breaking returns the changes which break existing command lines or config files
+func (diff *ManifestDiff) Breaking() []ManifestChange {
+	return diff.filter(true)
+}
+
+// NonBreaking returns the changes which keep existing command lines working
+func (diff *ManifestDiff) NonBreaking() []ManifestChange {
+	return diff.filter(false)
+}
+
+func (diff *ManifestDiff) filter(breaking bool) []ManifestChange {
+	var changes []ManifestChange
+	for _, change := range diff.Changes {
+		if change.Breaking == breaking {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// DiffManifests compares two manifests and classifies the changes of the flags.
+//
+// Flags are matched by their long name, or short name if they have no long name.
+// Removing a flag, removing or renaming its short name and changing its type are
+// breaking changes. Adding flags or short names and changing defaults are not.
+func DiffManifests(oldManifest, newManifest *Manifest) *ManifestDiff {
+	diff := &ManifestDiff{}
+
+	newFlags := make(map[string]ManifestFlag, len(newManifest.Flags))
+	for _, flag := range newManifest.Flags {
+		newFlags[manifestFlagKey(flag)] = flag
+	}
+	oldFlags := make(map[string]struct{}, len(oldManifest.Flags))
+
+	for _, oldFlag := range oldManifest.Flags {
+		key := manifestFlagKey(oldFlag)
+		oldFlags[key] = struct{}{}
+
+		newFlag, ok := newFlags[key]
+		if !ok {
+			diff.Changes = append(diff.Changes, ManifestChange{Kind: FlagRemoved, Flag: key, Breaking: true})
+			continue
+		}
+		if oldFlag.Short != newFlag.Short {
+			diff.Changes = append(diff.Changes, ManifestChange{Kind: ShortNameChanged, Flag: key, Old: oldFlag.Short, New: newFlag.Short, Breaking: oldFlag.Short != ""})
+		}
+		if oldFlag.Type != newFlag.Type {
+			diff.Changes = append(diff.Changes, ManifestChange{Kind: TypeChanged, Flag: key, Old: oldFlag.Type, New: newFlag.Type, Breaking: true})
+		}
+		if oldFlag.Default != newFlag.Default {
+			diff.Changes = append(diff.Changes, ManifestChange{Kind: DefaultChanged, Flag: key, Old: oldFlag.Default, New: newFlag.Default})
+		}
+	}
+
+	for _, newFlag := range newManifest.Flags {
+		key := manifestFlagKey(newFlag)
+		if _, ok := oldFlags[key]; !ok {
+			diff.Changes = append(diff.Changes, ManifestChange{Kind: FlagAdded, Flag: key})
+		}
+	}
+	return diff
+}
+
+// manifestFlagKey returns the name used to match a flag between manifests
+func manifestFlagKey(flag ManifestFlag) string {
+	if flag.Long != "" {
+		return flag.Long
+	}
+	return flag.Short
+}
+
+// ReadManifestFile reads a manifest from a JSON file
+func ReadManifestFile(filePath string) (*Manifest, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	manifest := &Manifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, errors.Wrap(err, filePath)
+	}
+	return manifest, nil
+}
+
+// TestingT is the subset of testing.TB used by CheckManifestCompatibility
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+	Logf(format string, args ...interface{})
+}
+
+// CheckManifestCompatibility compares the manifest of the flagset against a golden
+// manifest file committed to the repository, and fails the test on breaking changes.
+// Non-breaking changes are logged.
+//
+// The golden file is (re)written with the current manifest when the
+// GOFLAGS_UPDATE_MANIFEST environment variable is set, e.g. when a breaking
+// change is intended for a major release.
+func (flagSet *FlagSet) CheckManifestCompatibility(t TestingT, goldenPath string) {
+	t.Helper()
+
+	if os.Getenv(UpdateManifestEnv) != "" {
+		file, err := os.Create(goldenPath)
+		if err != nil {
+			t.Errorf("could not create golden manifest: %s", err)
+			return
+		}
+		defer file.Close()
+		if err := flagSet.WriteManifest(file); err != nil {
+			t.Errorf("could not write golden manifest: %s", err)
+		}
+		return
+	}
+
+	golden, err := ReadManifestFile(goldenPath)
+	if err != nil {
+		t.Errorf("could not read golden manifest (run with %s=1 to create it): %s", UpdateManifestEnv, err)
+		return
+	}
+	diff := DiffManifests(golden, flagSet.Manifest())
+	for _, change := range diff.NonBreaking() {
+		t.Logf("non-breaking flag change: %s", change)
+	}
+	for _, change := range diff.Breaking() {
+		t.Errorf("breaking flag change: %s", change)
+	}
+}
@@ -0,0 +1,99 @@
+package goflags
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffManifests(t *testing.T) {
+	old := &Manifest{Flags: []ManifestFlag{
+		{Long: "target", Short: "u", Type: "string"},
+		{Long: "threads", Short: "t", Type: "int", Default: "25"},
+		{Long: "output", Short: "o", Type: "string"},
+		{Long: "timeout", Type: "int", Default: "10"},
+		{Long: "silent", Type: "bool"},
+	}}
+	current := &Manifest{Flags: []ManifestFlag{
+		{Long: "target", Short: "u", Type: "string"},
+		{Long: "threads", Short: "c", Type: "int", Default: "50"},
+		{Long: "timeout", Type: "duration", Default: "10s"},
+		{Long: "silent", Short: "s", Type: "bool"},
+		{Long: "json", Short: "j", Type: "bool"},
+	}}
+
+	diff := DiffManifests(old, current)
+	require.Equal(t, []ManifestChange{
+		{Kind: ShortNameChanged, Flag: "threads", Old: "t", New: "c", Breaking: true},
+		{Kind: FlagRemoved, Flag: "output", Breaking: true},
+		{Kind: TypeChanged, Flag: "timeout", Old: "int", New: "duration", Breaking: true},
+	}, diff.Breaking())
+	require.Equal(t, []ManifestChange{
+		{Kind: DefaultChanged, Flag: "threads", Old: "25", New: "50"},
+		{Kind: DefaultChanged, Flag: "timeout", Old: "10", New: "10s"},
+		{Kind: ShortNameChanged, Flag: "silent", Old: "", New: "s"},
+		{Kind: FlagAdded, Flag: "json"},
+	}, diff.NonBreaking())
+
+	require.Equal(t, `short-name of flag -threads changed from "t" to "c"`, diff.Breaking()[0].String())
+	require.Equal(t, "flag -output was removed", diff.Breaking()[1].String())
+
+	require.Empty(t, DiffManifests(old, old).Changes)
+}
+
+type mockTestingT struct {
+	errors []string
+	logs   []string
+}
+
+func (m *mockTestingT) Helper() {}
+
+func (m *mockTestingT) Errorf(format string, args ...interface{}) {
+	m.errors = append(m.errors, fmt.Sprintf(format, args...))
+}
+
+func (m *mockTestingT) Logf(format string, args ...interface{}) {
+	m.logs = append(m.logs, fmt.Sprintf(format, args...))
+}
+
+func TestCheckManifestCompatibility(t *testing.T) {
+	goldenPath := filepath.Join(t.TempDir(), "flags.json")
+
+	flagSet := NewFlagSet()
+	var target string
+	var threads int
+	flagSet.StringVarP(&target, "target", "u", "", "target to scan")
+	flagSet.IntVarP(&threads, "threads", "t", 25, "number of threads")
+
+	mock := &mockTestingT{}
+	flagSet.CheckManifestCompatibility(mock, goldenPath)
+	require.Len(t, mock.errors, 1, "missing golden manifest should fail")
+
+	t.Setenv(UpdateManifestEnv, "1")
+	mock = &mockTestingT{}
+	flagSet.CheckManifestCompatibility(mock, goldenPath)
+	require.Empty(t, mock.errors)
+	require.FileExists(t, goldenPath)
+	os.Unsetenv(UpdateManifestEnv)
+
+	mock = &mockTestingT{}
+	flagSet.CheckManifestCompatibility(mock, goldenPath)
+	require.Empty(t, mock.errors)
+	require.Empty(t, mock.logs)
+
+	changedFlagSet := NewFlagSet()
+	var silent bool
+	changedFlagSet.StringVarP(&target, "target", "u", "", "target to scan")
+	changedFlagSet.IntVarP(&threads, "threads", "c", 25, "number of threads")
+	changedFlagSet.BoolVar(&silent, "silent", false, "silent output")
+
+	mock = &mockTestingT{}
+	changedFlagSet.CheckManifestCompatibility(mock, goldenPath)
+	require.Equal(t, []string{`breaking flag change: short-name of flag -threads changed from "t" to "c"`}, mock.errors)
+	require.Equal(t, []string{"non-breaking flag change: flag -silent was added"}, mock.logs)
+
+	tearDown(t.Name())
+}
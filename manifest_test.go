@@ -0,0 +1,97 @@
+package goflags
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestManifest(t *testing.T) {
+	t.Setenv("GOFLAGS_MANIFEST_TOKEN", "secret")
+	flagSet := NewFlagSet()
+	flagSet.SetDescription("test tool")
+	var target, severity, token string
+	var threads int
+	var oldFlag bool
+	flagSet.CreateGroup("input", "Input",
+		flagSet.StringVarP(&target, "target", "u", "", "target to scan"),
+	)
+	flagSet.EnumVarP(&severity, "severity", "s", EnumVariable(1), "severity to filter", AllowdTypes{"low": 0, "high": 1})
+	flagSet.IntVarP(&threads, "threads", "c", 25, "number of threads").Hidden()
+	flagSet.StringVarEnv(&token, "token", "tk", "anonymous", "GOFLAGS_MANIFEST_TOKEN", "api token")
+	flagSet.BoolVar(&oldFlag, "old", false, "old behaviour").Deprecated("use -target instead")
+
+	manifest := flagSet.Manifest()
+	require.Equal(t, "test tool", manifest.Description)
+	require.Equal(t, []ManifestGroup{{Name: "input", Description: "Input"}}, manifest.Groups)
+	require.Equal(t, []ManifestFlag{
+		{Long: "target", Short: "u", Type: "string", Group: "input", Usage: "target to scan"},
+		{Long: "severity", Short: "s", Type: "enum", Default: "high", Usage: "severity to filter", EnumValues: []string{"high", "low"}},
+		{Long: "threads", Short: "c", Type: "int", Default: "25", Usage: "number of threads", Hidden: true},
+		{Long: "token", Short: "tk", Type: "string", Default: "anonymous", Usage: "api token", Env: "GOFLAGS_MANIFEST_TOKEN"},
+		{Long: "old", Type: "bool", Usage: "old behaviour", Deprecated: "use -target instead"},
+	}, manifest.Flags, "the default of env flags should not be the value of the environment variable")
+	require.Equal(t, "secret", token)
+
+	tearDown(t.Name())
+}
+
+func TestManifestJSONHelp(t *testing.T) {
+	flagSet := NewFlagSet()
+	flagSet.SetDescription("test tool")
+	var target string
+	var threads int
+	flagSet.StringVarP(&target, "target", "u", "", "target to scan")
+	flagSet.IntVarP(&threads, "threads", "c", 25, "number of threads").Hidden()
+
+	output := &bytes.Buffer{}
+	flagSet.CommandLine.SetOutput(output)
+	os.Args = []string{os.Args[0], "-h", "-json"}
+	flagSet.usageFunc()
+
+	var manifest Manifest
+	require.Nil(t, json.Unmarshal(output.Bytes(), &manifest), "could not decode json help")
+	require.Equal(t, flagSet.Manifest(), &manifest)
+
+	jsonFlagSet := NewFlagSet()
+	var jsonOutput bool
+	jsonFlagSet.StringVarP(&target, "target", "u", "", "target to scan")
+	jsonFlagSet.BoolVar(&jsonOutput, "json", false, "json output")
+
+	output.Reset()
+	jsonFlagSet.CommandLine.SetOutput(output)
+	jsonFlagSet.usageFunc()
+	require.Contains(t, output.String(), "   -json               json output\n", "tools with a -json flag should keep the regular help")
+
+	tearDown(t.Name())
+}
+
+func TestHiddenFlagUsage(t *testing.T) {
+	t.Setenv("GOFLAGS_MANIFEST_TOKEN", "secret")
+	flagSet := NewFlagSet()
+	flagSet.SetConfigFilePath(filepath.Join(t.TempDir(), "config.yaml"))
+	var token string
+	var threads int
+	var oldFlag bool
+	flagSet.IntVarP(&threads, "threads", "c", 25, "number of threads").Hidden()
+	flagSet.StringVarEnv(&token, "token", "tk", "anonymous", "GOFLAGS_MANIFEST_TOKEN", "api token")
+	flagSet.BoolVar(&oldFlag, "old", false, "old behaviour").Deprecated("use -token instead")
+
+	output := &bytes.Buffer{}
+	flagSet.CommandLine.SetOutput(output)
+	os.Args = []string{os.Args[0], "-h"}
+	flagSet.usageFunc()
+	require.NotContains(t, output.String(), "-threads", "hidden flag should not be displayed")
+	require.Contains(t, output.String(), "   -tk, -token string  api token (default \"anonymous\")\n")
+	require.Contains(t, output.String(), "   -old                old behaviour (deprecated: use -token instead)\n")
+
+	require.Nil(t, flagSet.Parse("-threads", "5", "-old"))
+	require.Equal(t, 5, threads, "hidden flags should still be usable")
+	require.True(t, oldFlag)
+
+	tearDown(t.Name())
+}
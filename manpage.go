@@ -0,0 +1,177 @@
+package goflags
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	folderutil "github.com/projectdiscovery/utils/folder"
+)
+
+// WriteManPage writes a roff formatted man page for the flagset to the writer.
+//
+// The page contains the NAME, SYNOPSIS and DESCRIPTION sections built from the
+// description, one OPTIONS subsection per group, the environment variables read
+// by flags, the config file location and the examples followed by the custom help text.
+func (flagSet *FlagSet) WriteManPage(w io.Writer, section int) error {
+	toolName := getToolName()
+	buffer := &bytes.Buffer{}
+
+	fmt.Fprintf(buffer, ".TH %s %d\n", roffEscape(strings.ToUpper(toolName)), section)
+
+	buffer.WriteString(".SH NAME\n")
+	buffer.WriteString(roffEscape(toolName))
+	if summary := firstLine(flagSet.description); summary != "" {
+		buffer.WriteString(" \\- ")
+		buffer.WriteString(roffEscape(summary))
+	}
+	buffer.WriteString("\n")
+
+	buffer.WriteString(".SH SYNOPSIS\n")
+	fmt.Fprintf(buffer, ".B %s\n[\\fIflags\\fR]\n", roffEscape(toolName))
+	if argsUsage := flagSet.argsUsage(); argsUsage != "" {
+		fmt.Fprintf(buffer, "%s\n", roffEscape(argsUsage))
+	}
+
+	if !isEmpty(flagSet.description) {
+		buffer.WriteString(".SH DESCRIPTION\n")
+		writeRoffParagraphs(buffer, flagSet.description)
+	}
+
+	buffer.WriteString(".SH OPTIONS\n")
+	var envFlags []*FlagData
+	for _, group := range flagSet.groupedFlags() {
+		if len(group.flags) == 0 {
+			continue
+		}
+		if group.description != "" {
+			fmt.Fprintf(buffer, ".SS %s\n", roffEscape(normalizeGroupDescription(group.description)))
+		}
+		for _, data := range group.flags {
+			flagSet.writeManPageFlag(buffer, data)
+			if data.envName != "" {
+				envFlags = append(envFlags, data)
+			}
+		}
+	}
+
+	if len(envFlags) > 0 {
+		buffer.WriteString(".SH ENVIRONMENT\n")
+		for _, data := range envFlags {
+			fmt.Fprintf(buffer, ".TP\n.B %s\n", roffEscape(data.envName))
+			fmt.Fprintf(buffer, "Default value for %s.\n", roffEscape(flagSet.joinFlagNames(data)))
+		}
+	}
+
+	if configFilePath, err := flagSet.GetConfigFilePath(); err == nil {
+		buffer.WriteString(".SH FILES\n")
+		fmt.Fprintf(buffer, ".TP\n.I %s\n", roffEscape(tildeHomeDir(configFilePath)))
+		buffer.WriteString("Configuration file, created with commented default values on first run.\n")
+		buffer.WriteString("Command line flags take precedence over values from this file.\n")
+	}
+
+	examples := flagSet.allExamples()
+	if len(examples) > 0 || !isEmpty(flagSet.customHelpText) {
+		buffer.WriteString(".SH EXAMPLES\n")
+	}
+	for _, example := range examples {
+		fmt.Fprintf(buffer, ".PP\n%s:\n.PP\n.RS\n.nf\n%s\n.fi\n.RE\n", roffEscape(example.title), roffEscape(example.commandLine))
+	}
+	if !isEmpty(flagSet.customHelpText) {
+		buffer.WriteString(".nf\n")
+		for _, line := range strings.Split(strings.TrimSpace(flagSet.customHelpText), "\n") {
+			buffer.WriteString(roffEscape(line))
+			buffer.WriteString("\n")
+		}
+		buffer.WriteString(".fi\n")
+	}
+
+	_, err := w.Write(buffer.Bytes())
+	return err
+}
+
+// writeManPageFlag writes a tagged paragraph describing a single flag
+func (flagSet *FlagSet) writeManPageFlag(buffer *bytes.Buffer, data *FlagData) {
+	currentFlag := flagSet.lookupFlag(data)
+	if currentFlag == nil {
+		return
+	}
+	valueType := reflect.TypeOf(currentFlag.Value)
+
+	var names []string
+	for _, name := range flagSet.displayNames(data) {
+		names = append(names, "\\fB"+roffEscape(name)+"\\fR")
+	}
+	buffer.WriteString(".TP\n")
+	buffer.WriteString(strings.Join(names, ", "))
+	if flagType := usageFlagType(currentFlag, valueType); flagType != "" {
+		fmt.Fprintf(buffer, " \\fI%s\\fR", roffEscape(flagType))
+	}
+	buffer.WriteString("\n")
+
+	buffer.WriteString(roffEscape(flagUsage(currentFlag)))
+	buffer.WriteString("\n")
+	if defaultValue := usageDefaultValue(data, currentFlag, valueType); defaultValue != "" {
+		fmt.Fprintf(buffer, ".br\nDefault: %s\n", roffEscape(defaultValue))
+	}
+	if data.envName != "" {
+		fmt.Fprintf(buffer, ".br\nEnvironment: \\fB%s\\fR\n", roffEscape(data.envName))
+	}
+}
+
+// flagUsage returns the usage of a flag folded into a single line
+func flagUsage(currentFlag *flag.Flag) string {
+	_, usage := flag.UnquoteUsage(currentFlag)
+	return strings.Join(strings.Fields(usage), " ")
+}
+
+// writeRoffParagraphs writes text as roff paragraphs separated by blank lines
+func writeRoffParagraphs(buffer *bytes.Buffer, text string) {
+	for i, paragraph := range strings.Split(strings.TrimSpace(text), "\n\n") {
+		if i > 0 {
+			buffer.WriteString(".PP\n")
+		}
+		for _, line := range strings.Split(strings.TrimSpace(paragraph), "\n") {
+			buffer.WriteString(roffEscape(strings.TrimSpace(line)))
+			buffer.WriteString("\n")
+		}
+	}
+}
+
+// roffEscape escapes text so that it is rendered literally by roff
+func roffEscape(value string) string {
+	value = strings.ReplaceAll(value, "\\", "\\e")
+	value = strings.ReplaceAll(value, "-", "\\-")
+	if strings.HasPrefix(value, ".") || strings.HasPrefix(value, "'") {
+		value = "\\&" + value
+	}
+	return value
+}
+
+// joinFlagNames returns the dash prefixed names of a flag, e.g. "-u, -target"
+func (flagSet *FlagSet) joinFlagNames(data *FlagData) string {
+	return strings.Join(flagSet.displayNames(data), ", ")
+}
+
+// firstLine returns the first non empty line of text
+func firstLine(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// tildeHomeDir replaces the home directory prefix of a path with ~
+// so generated documentation doesn't depend on the user building it.
+func tildeHomeDir(filePath string) string {
+	homeDir := folderutil.HomeDirOrDefault("")
+	if homeDir != "" && strings.HasPrefix(filePath, homeDir) {
+		return "~" + strings.TrimPrefix(filePath, homeDir)
+	}
+	return filePath
+}
@@ -0,0 +1,45 @@
+package goflags
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteManPage(t *testing.T) {
+	flagSet := NewFlagSet()
+	flagSet.SetDescription("Test tool for man pages.\n\nIt has a second paragraph.")
+	flagSet.SetCustomHelpText("EXAMPLES:\n.tool -u example.com")
+
+	var target, token string
+	var silent bool
+	var threads int
+	flagSet.CreateGroup("input", "Input",
+		flagSet.StringVarP(&target, "target", "u", "", "target to scan"),
+	)
+	flagSet.CreateGroup("config", "Configuration",
+		flagSet.StringVarEnv(&token, "token", "tk", "", "TEST_MAN_TOKEN", "api token"),
+		flagSet.IntVarP(&threads, "threads", "c", 25, "number of threads"),
+	)
+	flagSet.BoolVar(&silent, "silent", false, "silent output")
+
+	output := &bytes.Buffer{}
+	require.Nil(t, flagSet.WriteManPage(output, 1))
+	page := output.String()
+
+	require.Contains(t, page, ".SH NAME\n")
+	require.Contains(t, page, " \\- Test tool for man pages.\n")
+	require.Contains(t, page, ".SH SYNOPSIS\n")
+	require.Contains(t, page, ".SH DESCRIPTION\nTest tool for man pages.\n.PP\nIt has a second paragraph.\n")
+	require.Contains(t, page, ".SS INPUT\n.TP\n\\fB\\-u\\fR, \\fB\\-target\\fR \\fIstring\\fR\ntarget to scan\n")
+	require.Contains(t, page, ".SS CONFIGURATION\n")
+	require.Contains(t, page, "number of threads\n.br\nDefault: 25\n")
+	require.Contains(t, page, ".br\nEnvironment: \\fBTEST_MAN_TOKEN\\fR\n")
+	require.Contains(t, page, ".SS OTHER OPTIONS\n.TP\n\\fB\\-silent\\fR\nsilent output\n")
+	require.Contains(t, page, ".SH ENVIRONMENT\n.TP\n.B TEST_MAN_TOKEN\nDefault value for \\-tk, \\-token.\n")
+	require.Contains(t, page, ".SH FILES\n")
+	require.Contains(t, page, ".SH EXAMPLES\n.nf\nEXAMPLES:\n\\&.tool \\-u example.com\n.fi\n")
+
+	tearDown(t.Name())
+}
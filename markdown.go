@@ -0,0 +1,143 @@
+package goflags
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// MarkdownUsageStartMarker marks the beginning of the generated usage block in a markdown file
+	MarkdownUsageStartMarker = "<!-- goflags:usage:start -->"
+	// MarkdownUsageEndMarker marks the end of the generated usage block in a markdown file
+	MarkdownUsageEndMarker = "<!-- goflags:usage:end -->"
+)
+
+// WriteMarkdown writes the grouped usage of the flagset as a markdown code block.
+//
+// The content of the block is the same as the help output of the tool,
+// which is the format used by the "Usage" section of most READMEs.
+func (flagSet *FlagSet) WriteMarkdown(w io.Writer) error {
+	buffer := &bytes.Buffer{}
+	buffer.WriteString("```console\n")
+	flagSet.writeUsage(buffer, getToolName())
+	buffer.WriteString("```\n")
+
+	_, err := w.Write(buffer.Bytes())
+	return err
+}
+
+// WriteMarkdownTable writes the grouped usage of the flagset as markdown tables,
+// one table per group with the flag names, type, default value and description,
+// followed by the examples of the flagset.
+func (flagSet *FlagSet) WriteMarkdownTable(w io.Writer) error {
+	buffer := &bytes.Buffer{}
+	for _, group := range flagSet.groupedFlags() {
+		if len(group.flags) == 0 {
+			continue
+		}
+		if buffer.Len() > 0 {
+			buffer.WriteString("\n")
+		}
+		if group.description != "" {
+			fmt.Fprintf(buffer, "### %s\n\n", group.description)
+		}
+		buffer.WriteString("| Flag | Type | Default | Description |\n")
+		buffer.WriteString("|------|------|---------|-------------|\n")
+		for _, data := range group.flags {
+			currentFlag := flagSet.lookupFlag(data)
+			if currentFlag == nil {
+				continue
+			}
+			valueType := reflect.TypeOf(currentFlag.Value)
+			fmt.Fprintf(buffer, "| `%s` | %s | %s | %s |\n",
+				flagSet.joinFlagNames(data),
+				markdownTableEscape(usageFlagType(currentFlag, valueType)),
+				markdownTableEscape(usageDefaultValue(data, currentFlag, valueType)),
+				markdownTableEscape(flagUsage(currentFlag)),
+			)
+		}
+	}
+
+	if examples := flagSet.allExamples(); len(examples) > 0 {
+		buffer.WriteString("\n### Examples\n")
+		for _, example := range examples {
+			fmt.Fprintf(buffer, "\n%s:\n\n```console\n%s\n```\n", example.title, example.commandLine)
+		}
+	}
+
+	_, err := w.Write(buffer.Bytes())
+	return err
+}
+
+// CheckMarkdownUsage verifies that the usage block between the usage markers
+// of a markdown file matches the current usage of the flagset.
+//
+// An error is returned if the markers are missing or the block is outdated,
+// which allows release processes to fail when documentation drifts.
+func (flagSet *FlagSet) CheckMarkdownUsage(filePath string) error {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+	updated, err := flagSet.replaceMarkdownUsage(string(content))
+	if err != nil {
+		return errors.Wrap(err, filePath)
+	}
+	if updated != string(content) {
+		return fmt.Errorf("%s: usage block is outdated", filePath)
+	}
+	return nil
+}
+
+// UpdateMarkdownUsage rewrites the usage block between the usage markers
+// of a markdown file with the current usage of the flagset.
+func (flagSet *FlagSet) UpdateMarkdownUsage(filePath string) error {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+	updated, err := flagSet.replaceMarkdownUsage(string(content))
+	if err != nil {
+		return errors.Wrap(err, filePath)
+	}
+	if updated == string(content) {
+		return nil
+	}
+	fileInfo, err := os.Stat(filePath)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filePath, []byte(updated), fileInfo.Mode())
+}
+
+// replaceMarkdownUsage replaces the content between the usage markers with the markdown usage
+func (flagSet *FlagSet) replaceMarkdownUsage(content string) (string, error) {
+	start := strings.Index(content, MarkdownUsageStartMarker)
+	if start == -1 {
+		return "", errors.New("usage start marker not found")
+	}
+	start += len(MarkdownUsageStartMarker)
+	end := strings.Index(content[start:], MarkdownUsageEndMarker)
+	if end == -1 {
+		return "", errors.New("usage end marker not found")
+	}
+	end += start
+
+	usage := &bytes.Buffer{}
+	if err := flagSet.WriteMarkdown(usage); err != nil {
+		return "", err
+	}
+	return content[:start] + "\n" + usage.String() + content[end:], nil
+}
+
+// markdownTableEscape escapes a value for use inside a markdown table cell
+func markdownTableEscape(value string) string {
+	value = strings.ReplaceAll(value, "|", "\\|")
+	return strings.ReplaceAll(value, "\n", " ")
+}
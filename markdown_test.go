@@ -0,0 +1,115 @@
+package goflags
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteMarkdown(t *testing.T) {
+	flagSet := NewFlagSet()
+	flagSet.SetDescription("Markdown test tool")
+	var target string
+	var threads int
+	var silent bool
+	flagSet.CreateGroup("input", "Input",
+		flagSet.StringVarP(&target, "target", "u", "", "target to scan"),
+	)
+	flagSet.CreateGroup("config", "Config",
+		flagSet.IntVarP(&threads, "threads", "c", 25, "number of threads | workers"),
+		flagSet.BoolVar(&silent, "silent", false, "silent output"),
+	)
+
+	output := &bytes.Buffer{}
+	require.Nil(t, flagSet.WriteMarkdown(output))
+
+	expected := "```console\n" +
+		"Markdown test tool\n\n" +
+		"Usage:\n  " + getToolName() + " [flags]\n\n" +
+		"Flags:\n" +
+		"INPUT:\n" +
+		"   -u, -target string  target to scan\n\n" +
+		"CONFIG:\n" +
+		"   -c, -threads int  number of threads | workers (default 25)\n" +
+		"   -silent           silent output\n\n" +
+		"```\n"
+	require.Equal(t, expected, output.String())
+
+	help := &bytes.Buffer{}
+	flagSet.CommandLine.SetOutput(help)
+	os.Args = []string{getToolName(), "-h"}
+	flagSet.usageFunc()
+	require.Equal(t, "```console\n"+help.String()+"```\n", output.String(), "markdown usage should be the help output")
+
+	tearDown(t.Name())
+}
+
+func TestWriteMarkdownTable(t *testing.T) {
+	flagSet := NewFlagSet()
+	flagSet.SetDescription("Markdown test tool")
+	var target string
+	var threads int
+	var silent bool
+	flagSet.CreateGroup("input", "Input",
+		flagSet.StringVarP(&target, "target", "u", "", "target to scan"),
+	)
+	flagSet.CreateGroup("config", "Config",
+		flagSet.IntVarP(&threads, "threads", "c", 25, "number of threads | workers"),
+		flagSet.BoolVar(&silent, "silent", false, "silent output"),
+	)
+
+	output := &bytes.Buffer{}
+	require.Nil(t, flagSet.WriteMarkdownTable(output))
+
+	expected := `### Input
+
+| Flag | Type | Default | Description |
+|------|------|---------|-------------|
+| ` + "`-u, -target`" + ` | string |  | target to scan |
+
+### Config
+
+| Flag | Type | Default | Description |
+|------|------|---------|-------------|
+| ` + "`-c, -threads`" + ` | int | 25 | number of threads \| workers |
+| ` + "`-silent`" + ` |  |  | silent output |
+`
+	require.Equal(t, expected, output.String())
+
+	flagSet = NewFlagSet()
+	flagSet.CreateGroup("output", "Output")
+	flagSet.StringVarP(&target, "target", "u", "", "target to scan")
+	output.Reset()
+	require.Nil(t, flagSet.WriteMarkdownTable(output))
+	require.True(t, strings.HasPrefix(output.String(), "### other options\n\n"), "empty groups should not add separators")
+
+	tearDown(t.Name())
+}
+
+func TestMarkdownUsageSync(t *testing.T) {
+	flagSet := NewFlagSet()
+	var target string
+	flagSet.StringVarP(&target, "target", "u", "", "target to scan")
+	readme := filepath.Join(t.TempDir(), "README.md")
+
+	content := "# Tool\n\n" + MarkdownUsageStartMarker + "\nstale usage\n" + MarkdownUsageEndMarker + "\n\nfooter\n"
+	require.Nil(t, os.WriteFile(readme, []byte(content), 0644))
+
+	require.NotNil(t, flagSet.CheckMarkdownUsage(readme), "stale usage block was not detected")
+	require.Nil(t, flagSet.UpdateMarkdownUsage(readme))
+	require.Nil(t, flagSet.CheckMarkdownUsage(readme))
+
+	updated, err := os.ReadFile(readme)
+	require.Nil(t, err)
+	require.True(t, strings.HasPrefix(string(updated), "# Tool\n\n"+MarkdownUsageStartMarker+"\n```console\n"))
+	require.True(t, strings.HasSuffix(string(updated), "```\n"+MarkdownUsageEndMarker+"\n\nfooter\n"))
+
+	require.Nil(t, os.WriteFile(readme, []byte("# Tool\n"), 0644))
+	require.NotNil(t, flagSet.CheckMarkdownUsage(readme), "missing markers were not reported")
+
+	tearDown(t.Name())
+}
@@ -5,20 +5,27 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
 
 	_ "embed"
-
-	mapsutil "github.com/projectdiscovery/utils/maps"
-	stringsutil "github.com/projectdiscovery/utils/strings"
-	"golang.org/x/exp/maps"
 )
 
+//go:generate go run ./cmd/portsfrequency -services /usr/share/nmap/nmap-services -output ports_frequency.json
+
 var (
 	//go:embed ports_data.json
 	portsData string
+	// portsFrequencyData holds the tcp and udp ports ranked by the open frequency of nmap-services
+	//go:embed ports_frequency.json
+	portsFrequencyData string
+
+	portsRankingOnce sync.Once
+	portsRanking     map[Protocol][]int
 
-	portOptionDefaultValues map[*Port]map[int]struct{}
-	servicesMap             map[string][]int
+	// servicesMap holds the port services shared by all the flag sets of the process,
+	// servicesMutex guards it as services can be registered at any time
+	servicesMap   map[string][]int
+	servicesMutex sync.RWMutex
 )
 
 func init() {
@@ -26,58 +33,160 @@ func init() {
 	if err != nil {
 		panic(err)
 	}
+}
+
+// Protocol is the transport protocol of a port
+type Protocol int
+
+const (
+	// TCP is the protocol of ports without a protocol prefix or with a T: or TCP: prefix
+	TCP Protocol = iota
+	// UDP is the protocol of ports with a U: or UDP: prefix
+	UDP
+)
+
+func (protocol Protocol) String() string {
+	switch protocol {
+	case UDP:
+		return "udp"
+	default:
+		return "tcp"
+	}
+}
 
-	portOptionDefaultValues = make(map[*Port]map[int]struct{})
+// PortEntry is a port number with its protocol
+type PortEntry struct {
+	Port     int
+	Protocol Protocol
 }
 
-// Port is a list of unique ports in a normalized format
+func (entry PortEntry) String() string {
+	if entry.Protocol == UDP {
+		return fmt.Sprintf("U:%d", entry.Port)
+	}
+	return strconv.Itoa(entry.Port)
+}
+
+// Port is a list of unique ports in a normalized format,
+// stored as one bit per port and protocol
 type Port struct {
-	kv map[int]struct{}
+	ports    *portSet
+	excluded *portSet
+	// isDefault is true while the ports are the default value of the flag
+	isDefault bool
 }
 
+// String returns the ports collapsed into ranges, e.g. 1-1024,8080,U:53
 func (port Port) String() string {
-	defaultBuilder := &strings.Builder{}
-	defaultBuilder.WriteString("(")
-
-	var items string
-	for k := range port.kv {
-		items += fmt.Sprintf("%d,", k)
+	if port.ports == nil {
+		return ""
 	}
-	defaultBuilder.WriteString(stringsutil.TrimSuffixAny(items, ",", "="))
-	defaultBuilder.WriteString(")")
-	return defaultBuilder.String()
+	return port.ports.String()
 }
 
 // Set inserts a value to the port map. A number of formats are accepted.
+// Exclusions are kept across values, so they apply whatever the order of values.
+// The first value with ports replaces the default ports of the flag.
 func (port *Port) Set(value string) error {
-	newKv := make(map[int]struct{})
-	port.normalizePortValue(newKv, value)
+	newPorts := &portSet{}
+	excluded := &portSet{}
+	if err := port.normalizePortValue(newPorts, excluded, value); err != nil {
+		return err
+	}
 
 	// if new values are provided, we remove default ones
-	if defaultValue, ok := portOptionDefaultValues[port]; ok {
-		if maps.Equal(port.kv, defaultValue) {
-			port.kv = make(map[int]struct{})
-		}
+	if port.isDefault && !newPorts.isEmpty() {
+		port.ports = nil
+		port.isDefault = false
 	}
 
-	port.kv = mapsutil.Merge(port.kv, newKv)
+	if port.ports == nil {
+		port.ports = newPorts
+	} else {
+		port.ports.union(newPorts)
+	}
+	if !excluded.isEmpty() {
+		if port.excluded == nil {
+			port.excluded = excluded
+		} else {
+			port.excluded.union(excluded)
+		}
+	}
+	if port.excluded != nil {
+		port.ports.difference(port.excluded)
+	}
 
 	return nil
 }
 
-// AsPorts returns the ports list after normalization
+// Contains returns true if the port is in the list for any protocol
+func (port *Port) Contains(p int) bool {
+	if port.ports == nil {
+		return false
+	}
+	return port.ports.tcp.contains(p) || port.ports.udp.contains(p)
+}
+
+// Count returns the number of distinct port numbers in the list
+func (port *Port) Count() int {
+	if port.ports == nil {
+		return 0
+	}
+	return port.ports.count()
+}
+
+// AsPorts returns the sorted ports list after normalization, with ports
+// given for both protocols listed once
 func (port *Port) AsPorts() []int {
-	if port.kv == nil {
+	if port.ports == nil {
 		return nil
 	}
-	ports := make([]int, 0, len(port.kv))
-	for k := range port.kv {
-		ports = append(ports, k)
+	ports := make([]int, 0, port.ports.count())
+	port.ports.all().forEach(func(p int) {
+		ports = append(ports, p)
+	})
+	return ports
+}
+
+// AsPortsWithProtocol returns the ports list with their protocol
+// after normalization, sorted by port and protocol
+func (port *Port) AsPortsWithProtocol() []PortEntry {
+	if port.ports == nil {
+		return nil
+	}
+	var entries []PortEntry
+	port.ports.all().forEach(func(p int) {
+		for _, protocol := range []Protocol{TCP, UDP} {
+			if port.ports.protocol(protocol).contains(p) {
+				entries = append(entries, PortEntry{Port: p, Protocol: protocol})
+			}
+		}
+	})
+	return entries
+}
+
+// AsTCPPorts returns the sorted list of TCP ports
+func (port *Port) AsTCPPorts() []int {
+	return port.asProtocolPorts(TCP)
+}
+
+// AsUDPPorts returns the sorted list of UDP ports
+func (port *Port) AsUDPPorts() []int {
+	return port.asProtocolPorts(UDP)
+}
+
+func (port *Port) asProtocolPorts(protocol Protocol) []int {
+	if port.ports == nil {
+		return nil
 	}
+	var ports []int
+	port.ports.protocol(protocol).forEach(func(p int) {
+		ports = append(ports, p)
+	})
 	return ports
 }
 
-// normalizePortValues normalizes and returns a list of ports for a value.
+// normalizePortValues normalizes a value into the ports it includes and excludes.
 //
 // Supported values -
 //
@@ -85,107 +194,233 @@ func (port *Port) AsPorts() []int {
 //	1-10 => ports: 1 to 10
 //	1- => ports: 1 to 65535
 //	-/*/full => ports: 1 to 65535
-//	topxxx => ports: top most xxx common ports
+//	top-xxx => ports: top most xxx common ports
+//	top-xxx-udp => ports: top most xxx common udp ports
 //	ftp,http => ports: 21, 80
 //	ftp* => ports: 20, 21, 574, 989, 990, 8021
 //	U:53,T:25 => ports: 53 udp, 25 tcp
-func (port *Port) normalizePortValue(portsMap map[int]struct{}, value string) {
+//	U:dns,T:1-1024 => ports: 53 udp, 1 to 1024 tcp
+//	top-1000,!22,!8000-8100 => ports: top 1000 except 22 and 8000 to 8100
+//
+// Exclusions without protocol prefix exclude the ports of both protocols.
+func (port *Port) normalizePortValue(portsSet, excludedSet *portSet, value string) error {
+	values := strings.Split(value, ",")
+	for _, item := range values {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		target := portsSet
+		excluded := strings.HasPrefix(item, "!")
+		if excluded {
+			item = strings.TrimPrefix(item, "!")
+			target = excludedSet
+		}
+		if ports, ok := lookupPortService(item); ok {
+			// Handle ftp,http,etc service names, some of which contain a colon
+			port.appendPortsToKV(target, TCP, ports)
+			if excluded {
+				port.appendPortsToKV(target, UDP, ports)
+			}
+			continue
+		}
+		protocol, rest, hasPrefix, err := parseProtocolPrefix(item)
+		if err != nil {
+			return err
+		}
+		if err := port.normalizePortItem(target, protocol, rest); err != nil {
+			return err
+		}
+		if excluded && !hasPrefix {
+			_ = port.normalizePortItem(target, UDP, rest)
+		}
+	}
+	return nil
+}
+
+// normalizePortItem normalizes a single item without protocol prefix
+func (port *Port) normalizePortItem(portsSet *portSet, protocol Protocol, item string) error {
 	// Handle top-xxx/*/- cases
-	switch value {
+	switch item {
 	case "full", "-", "*":
-		value = portsFull
-	case "top-100":
-		value = portsNmapTop100
-	case "top-1000":
-		value = portsNmapTop1000
+		item = portsFull
+	}
+	if strings.HasPrefix(item, "top-") {
+		return port.parseTopPorts(portsSet, protocol, item)
 	}
 
-	values := strings.Split(value, ",")
-	for _, item := range values {
-		if ports, ok := servicesMap[item]; ok {
-			// Handle ftp,http,etc service names
-			port.appendPortsToKV(portsMap, ports)
-		} else if strings.Contains(item, ":") {
-			// Handle colon : based name like TCP:443
-			port.parsePortColonSeparated(portsMap, item)
-		} else if strings.HasSuffix(item, "*") {
-			// Handle wildcard service names
-			port.parseWildcardService(portsMap, item)
-		} else if strings.Contains(item, "-") {
-			// Handle dash based separated items
-			port.parsePortDashSeparated(portsMap, item)
-		} else {
-			// Handle normal ports
-			port.parsePortNumberItem(portsMap, item)
+	if ports, ok := lookupPortService(item); ok {
+		// Handle ftp,http,etc service names
+		port.appendPortsToKV(portsSet, protocol, ports)
+		return nil
+	} else if strings.HasSuffix(item, "*") {
+		// Handle wildcard service names
+		return port.parseWildcardService(portsSet, protocol, item)
+	} else if strings.Contains(item, "-") {
+		// Handle dash based separated items
+		return port.parsePortDashSeparated(portsSet, protocol, item)
+	}
+	// Handle normal ports
+	return port.parsePortNumberItem(portsSet, protocol, item)
+}
+
+// parseTopPorts parses top-N items, optionally suffixed by -tcp or -udp,
+// into the N most common ports of the protocol. N is limited to the ports
+// ranked by the frequency data, except for the top 1000 tcp ports and all the ports.
+func (port *Port) parseTopPorts(portsSet *portSet, protocol Protocol, item string) error {
+	count := strings.TrimPrefix(item, "top-")
+	if value, ok := strings.CutSuffix(count, "-udp"); ok {
+		count, protocol = value, UDP
+	} else if value, ok := strings.CutSuffix(count, "-tcp"); ok {
+		count, protocol = value, TCP
+	}
+	parsed, err := strconv.Atoi(count)
+	if err != nil || parsed < 1 || parsed > 65535 {
+		return fmt.Errorf("invalid top ports %q, expected a count between 1 and 65535", item)
+	}
+
+	ranking := rankedPorts(protocol)
+	switch {
+	case parsed <= len(ranking):
+		port.appendPortsToKV(portsSet, protocol, ranking[:parsed])
+	case parsed == 65535:
+		return port.parsePortDashSeparated(portsSet, protocol, portsFull)
+	case parsed == 1000 && protocol == TCP:
+		// the nmap top 1000 tcp ports are known as a list without their ranking
+		for _, item := range strings.Split(portsNmapTop1000, ",") {
+			if err := port.normalizePortItem(portsSet, protocol, item); err != nil {
+				return err
+			}
 		}
+	default:
+		return fmt.Errorf("invalid top ports %q, the frequency data ranks the %d most common %s ports", item, len(ranking), protocol)
 	}
+	return nil
+}
+
+// rankedPorts returns the ports of a protocol ranked by the frequency data, most common first
+func rankedPorts(protocol Protocol) []int {
+	portsRankingOnce.Do(func() {
+		var frequency struct {
+			TCP []int `json:"tcp"`
+			UDP []int `json:"udp"`
+		}
+		if err := json.Unmarshal([]byte(portsFrequencyData), &frequency); err != nil {
+			panic(err)
+		}
+		portsRanking = map[Protocol][]int{
+			TCP: frequency.TCP,
+			UDP: frequency.UDP,
+		}
+	})
+	return portsRanking[protocol]
 }
 
-func (port *Port) appendPortsToKV(portsMap map[int]struct{}, ports []int) {
+func (port *Port) appendPortsToKV(portsSet *portSet, protocol Protocol, ports []int) {
 	for _, p := range ports {
-		portsMap[p] = struct{}{}
+		portsSet.protocol(protocol).add(p)
 	}
 }
 
 // parseWildcardService parses wildcard based service names
-func (port *Port) parseWildcardService(portsMap map[int]struct{}, item string) {
+func (port *Port) parseWildcardService(portsSet *portSet, protocol Protocol, item string) error {
 	stripped := strings.TrimSuffix(item, "*")
+	var found bool
+	servicesMutex.RLock()
+	defer servicesMutex.RUnlock()
 	for service, ports := range servicesMap {
 		if strings.HasPrefix(service, stripped) {
-			port.appendPortsToKV(portsMap, ports)
+			port.appendPortsToKV(portsSet, protocol, ports)
+			found = true
 		}
 	}
+	if !found {
+		return fmt.Errorf("no service matches %q", item)
+	}
+	return nil
+}
+
+// lookupPortService returns the ports of a service name
+func lookupPortService(name string) ([]int, bool) {
+	servicesMutex.RLock()
+	defer servicesMutex.RUnlock()
+	ports, ok := servicesMap[name]
+	return ports, ok
 }
 
 // parsePortDashSeparated parses dash separated ports
-func (port *Port) parsePortDashSeparated(portsMap map[int]struct{}, item string) {
+func (port *Port) parsePortDashSeparated(portsSet *portSet, protocol Protocol, item string) error {
 	parts := strings.Split(item, "-")
+	if len(parts) != 2 {
+		return fmt.Errorf("invalid port range %q", item)
+	}
 	// Handle x- scenarios
-	if len(parts) == 2 && parts[1] == "" {
-		port.parsePortPairItems(portsMap, parts[0], "65535")
+	if parts[1] == "" {
+		parts[1] = "65535"
 	}
 	// Handle x-x port pairs
-	if len(parts) == 2 {
-		port.parsePortPairItems(portsMap, parts[0], parts[1])
-	}
+	return port.parsePortPairItems(portsSet, protocol, parts[0], parts[1])
 }
 
-// parsePortColonSeparated parses colon separated ports
-func (port *Port) parsePortColonSeparated(portsMap map[int]struct{}, item string) {
-	items := strings.Split(item, ":")
-	if len(items) == 2 {
-		parsed, err := strconv.Atoi(items[1])
-		if err == nil && parsed > 0 {
-			portsMap[parsed] = struct{}{}
-		}
+// parseProtocolPrefix splits the protocol prefix of colon separated items like U:53 or TCP:443.
+// Items without a prefix are TCP.
+func parseProtocolPrefix(item string) (Protocol, string, bool, error) {
+	prefix, rest, found := strings.Cut(item, ":")
+	if !found {
+		return TCP, item, false, nil
+	}
+	switch strings.ToLower(prefix) {
+	case "t", "tcp":
+		return TCP, rest, true, nil
+	case "u", "udp":
+		return UDP, rest, true, nil
+	default:
+		return TCP, "", false, fmt.Errorf("unknown protocol %q in %q, expected T, TCP, U or UDP", prefix, item)
 	}
 }
 
 // parsePortNumberItem parses a single port number
-func (port *Port) parsePortNumberItem(portsMap map[int]struct{}, item string) {
-	parsed, err := strconv.Atoi(item)
-	if err == nil && parsed > 0 {
-		portsMap[parsed] = struct{}{}
+func (port *Port) parsePortNumberItem(portsSet *portSet, protocol Protocol, item string) error {
+	parsed, err := parsePortNumber(item)
+	if err != nil {
+		return err
 	}
+	portsSet.protocol(protocol).add(parsed)
+	return nil
 }
 
 // parsePortPairItems parses port x-x pair items
-func (port *Port) parsePortPairItems(portsMap map[int]struct{}, first, second string) {
-	firstParsed, err := strconv.Atoi(first)
+func (port *Port) parsePortPairItems(portsSet *portSet, protocol Protocol, first, second string) error {
+	firstParsed, err := parsePortNumber(first)
+	if err != nil {
+		return err
+	}
+	secondParsed, err := parsePortNumber(second)
 	if err != nil {
-		return
+		return err
 	}
-	secondParsed, err := strconv.Atoi(second)
+	if firstParsed > secondParsed {
+		return fmt.Errorf("invalid port range %s-%s: start is greater than end", first, second)
+	}
+	portsSet.protocol(protocol).addRange(firstParsed, secondParsed)
+	return nil
+}
+
+// parsePortNumber parses a port number between 1 and 65535
+func parsePortNumber(item string) (int, error) {
+	parsed, err := strconv.Atoi(item)
 	if err != nil {
-		return
+		return 0, fmt.Errorf("invalid port %q", item)
 	}
-	for i := firstParsed; i <= secondParsed; i++ {
-		portsMap[i] = struct{}{}
+	if parsed < 1 || parsed > 65535 {
+		return 0, fmt.Errorf("port %d is out of range 1-65535", parsed)
 	}
+	return parsed, nil
 }
 
 const (
-	portsFull        = "1-65535"
-	portsNmapTop100  = "7,9,13,21-23,25-26,37,53,79-81,88,106,110-111,113,119,135,139,143-144,179,199,389,427,443-445,465,513-515,543-544,548,554,587,631,646,873,990,993,995,1025-1029,1110,1433,1720,1723,1755,1900,2000-2001,2049,2121,2717,3000,3128,3306,3389,3986,4899,5000,5009,5051,5060,5101,5190,5357,5432,5631,5666,5800,5900,6000-6001,6646,7070,8000,8008-8009,8080-8081,8443,8888,9100,9999-10000,32768,49152-49157"
+	portsFull = "1-65535"
+	// portsNmapTop1000 are the nmap top 1000 tcp ports, used for top-1000 when
+	// the frequency data does not rank as many ports
 	portsNmapTop1000 = "1,3-4,6-7,9,13,17,19-26,30,32-33,37,42-43,49,53,70,79-85,88-90,99-100,106,109-111,113,119,125,135,139,143-144,146,161,163,179,199,211-212,222,254-256,259,264,280,301,306,311,340,366,389,406-407,416-417,425,427,443-445,458,464-465,481,497,500,512-515,524,541,543-545,548,554-555,563,587,593,616-617,625,631,636,646,648,666-668,683,687,691,700,705,711,714,720,722,726,749,765,777,783,787,800-801,808,843,873,880,888,898,900-903,911-912,981,987,990,992-993,995,999-1002,1007,1009-1011,1021-1100,1102,1104-1108,1110-1114,1117,1119,1121-1124,1126,1130-1132,1137-1138,1141,1145,1147-1149,1151-1152,1154,1163-1166,1169,1174-1175,1183,1185-1187,1192,1198-1199,1201,1213,1216-1218,1233-1234,1236,1244,1247-1248,1259,1271-1272,1277,1287,1296,1300-1301,1309-1311,1322,1328,1334,1352,1417,1433-1434,1443,1455,1461,1494,1500-1501,1503,1521,1524,1533,1556,1580,1583,1594,1600,1641,1658,1666,1687-1688,1700,1717-1721,1723,1755,1761,1782-1783,1801,1805,1812,1839-1840,1862-1864,1875,1900,1914,1935,1947,1971-1972,1974,1984,1998-2010,2013,2020-2022,2030,2033-2035,2038,2040-2043,2045-2049,2065,2068,2099-2100,2103,2105-2107,2111,2119,2121,2126,2135,2144,2160-2161,2170,2179,2190-2191,2196,2200,2222,2251,2260,2288,2301,2323,2366,2381-2383,2393-2394,2399,2401,2492,2500,2522,2525,2557,2601-2602,2604-2605,2607-2608,2638,2701-2702,2710,2717-2718,2725,2800,2809,2811,2869,2875,2909-2910,2920,2967-2968,2998,3000-3001,3003,3005-3007,3011,3013,3017,3030-3031,3052,3071,3077,3128,3168,3211,3221,3260-3261,3268-3269,3283,3300-3301,3306,3322-3325,3333,3351,3367,3369-3372,3389-3390,3404,3476,3493,3517,3527,3546,3551,3580,3659,3689-3690,3703,3737,3766,3784,3800-3801,3809,3814,3826-3828,3851,3869,3871,3878,3880,3889,3905,3914,3918,3920,3945,3971,3986,3995,3998,4000-4006,4045,4111,4125-4126,4129,4224,4242,4279,4321,4343,4443-4446,4449,4550,4567,4662,4848,4899-4900,4998,5000-5004,5009,5030,5033,5050-5051,5054,5060-5061,5080,5087,5100-5102,5120,5190,5200,5214,5221-5222,5225-5226,5269,5280,5298,5357,5405,5414,5431-5432,5440,5500,5510,5544,5550,5555,5560,5566,5631,5633,5666,5678-5679,5718,5730,5800-5802,5810-5811,5815,5822,5825,5850,5859,5862,5877,5900-5904,5906-5907,5910-5911,5915,5922,5925,5950,5952,5959-5963,5987-5989,5998-6007,6009,6025,6059,6100-6101,6106,6112,6123,6129,6156,6346,6389,6502,6510,6543,6547,6565-6567,6580,6646,6666-6669,6689,6692,6699,6779,6788-6789,6792,6839,6881,6901,6969,7000-7002,7004,7007,7019,7025,7070,7100,7103,7106,7200-7201,7402,7435,7443,7496,7512,7625,7627,7676,7741,7777-7778,7800,7911,7920-7921,7937-7938,7999-8002,8007-8011,8021-8022,8031,8042,8045,8080-8090,8093,8099-8100,8180-8181,8192-8194,8200,8222,8254,8290-8292,8300,8333,8383,8400,8402,8443,8500,8600,8649,8651-8652,8654,8701,8800,8873,8888,8899,8994,9000-9003,9009-9011,9040,9050,9071,9080-9081,9090-9091,9099-9103,9110-9111,9200,9207,9220,9290,9415,9418,9485,9500,9502-9503,9535,9575,9593-9595,9618,9666,9876-9878,9898,9900,9917,9929,9943-9944,9968,9998-10004,10009-10010,10012,10024-10025,10082,10180,10215,10243,10566,10616-10617,10621,10626,10628-10629,10778,11110-11111,11967,12000,12174,12265,12345,13456,13722,13782-13783,14000,14238,14441-14442,15000,15002-15004,15660,15742,16000-16001,16012,16016,16018,16080,16113,16992-16993,17877,17988,18040,18101,18988,19101,19283,19315,19350,19780,19801,19842,20000,20005,20031,20221-20222,20828,21571,22939,23502,24444,24800,25734-25735,26214,27000,27352-27353,27355-27356,27715,28201,30000,30718,30951,31038,31337,32768-32785,33354,33899,34571-34573,35500,38292,40193,40911,41511,42510,44176,44442-44443,44501,45100,48080,49152-49161,49163,49165,49167,49175-49176,49400,49999-50003,50006,50300,50389,50500,50636,50800,51103,51493,52673,52822,52848,52869,54045,54328,55055-55056,55555,55600,56737-56738,57294,57797,58080,60020,60443,61532,61900,62078,63331,64623,64680,65000,65129,65389"
 )
@@ -0,0 +1,119 @@
+package goflags
+
+import (
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// portBitset is a set of the ports 0-65535 with one bit per port
+type portBitset [65536 / 64]uint64
+
+func (bitset *portBitset) add(port int) {
+	bitset[port/64] |= 1 << (port % 64)
+}
+
+// addRange adds the ports from first to last, both included
+func (bitset *portBitset) addRange(first, last int) {
+	for port := first; port <= last; port++ {
+		bitset.add(port)
+	}
+}
+
+func (bitset *portBitset) contains(port int) bool {
+	if port < 0 || port > 65535 {
+		return false
+	}
+	return bitset[port/64]&(1<<(port%64)) != 0
+}
+
+// forEach calls fn for every port of the set in ascending order
+func (bitset *portBitset) forEach(fn func(port int)) {
+	for i, word := range bitset {
+		for word != 0 {
+			fn(i*64 + bits.TrailingZeros64(word))
+			word &= word - 1
+		}
+	}
+}
+
+// portSet is a set of ports of both protocols
+type portSet struct {
+	tcp portBitset
+	udp portBitset
+}
+
+func (set *portSet) protocol(protocol Protocol) *portBitset {
+	if protocol == UDP {
+		return &set.udp
+	}
+	return &set.tcp
+}
+
+func (set *portSet) isEmpty() bool {
+	return *set == portSet{}
+}
+
+// union adds the ports of other to the set
+func (set *portSet) union(other *portSet) {
+	for i := range set.tcp {
+		set.tcp[i] |= other.tcp[i]
+		set.udp[i] |= other.udp[i]
+	}
+}
+
+// difference removes the ports of other from the set
+func (set *portSet) difference(other *portSet) {
+	for i := range set.tcp {
+		set.tcp[i] &^= other.tcp[i]
+		set.udp[i] &^= other.udp[i]
+	}
+}
+
+// count returns the number of distinct port numbers of both protocols
+func (set *portSet) count() int {
+	var count int
+	for i := range set.tcp {
+		count += bits.OnesCount64(set.tcp[i] | set.udp[i])
+	}
+	return count
+}
+
+// all returns the ports of both protocols in ascending order
+func (set *portSet) all() *portBitset {
+	all := set.tcp
+	for i := range all {
+		all[i] |= set.udp[i]
+	}
+	return &all
+}
+
+// String returns the ports collapsed into ranges, UDP ones prefixed by U:, e.g. 1-1024,8080,U:53
+func (set *portSet) String() string {
+	var items []string
+	for _, protocol := range []Protocol{TCP, UDP} {
+		prefix := ""
+		if protocol == UDP {
+			prefix = "U:"
+		}
+		first, last := -1, -1
+		flush := func() {
+			switch {
+			case first == -1:
+			case first == last:
+				items = append(items, prefix+strconv.Itoa(first))
+			default:
+				items = append(items, prefix+strconv.Itoa(first)+"-"+strconv.Itoa(last))
+			}
+		}
+		set.protocol(protocol).forEach(func(port int) {
+			if port != last+1 || first == -1 {
+				flush()
+				first = port
+			}
+			last = port
+		})
+		flush()
+	}
+	return strings.Join(items, ",")
+}
@@ -0,0 +1,191 @@
+package goflags
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	mapsutil "github.com/projectdiscovery/utils/maps"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/maps"
+)
+
+func TestPortBitset(t *testing.T) {
+	port := &Port{}
+	require.Nil(t, port.Set("8080,1-1024,U:53,U:161-162,443"))
+	require.Equal(t, "1-1024,8080,U:53,U:161-162", port.String())
+	require.Equal(t, 1025, port.Count())
+	require.True(t, port.Contains(1))
+	require.True(t, port.Contains(8080))
+	require.False(t, port.Contains(1025))
+	require.True(t, port.Contains(161), "udp ports should be contained")
+	require.False(t, port.Contains(65536))
+
+	ports := port.AsPorts()
+	require.Len(t, ports, 1025)
+	require.IsIncreasing(t, ports)
+
+	roundTrip := &Port{}
+	require.Nil(t, roundTrip.Set(port.String()))
+	require.Equal(t, port.AsPortsWithProtocol(), roundTrip.AsPortsWithProtocol())
+
+	full := &Port{}
+	require.Nil(t, full.Set("full"))
+	require.Equal(t, "1-65535", full.String())
+	require.Equal(t, 65535, full.Count())
+	require.True(t, full.Contains(65535))
+
+	require.Equal(t, "", (&Port{}).String())
+	require.Zero(t, (&Port{}).Count())
+	require.False(t, (&Port{}).Contains(80))
+}
+
+// legacyPort is the map based implementation Port had before the bitset,
+// kept unchanged as the benchmark baseline except for the services lock
+type legacyPort struct {
+	kv map[int]struct{}
+}
+
+var legacyPortDefaultValues = make(map[*legacyPort]map[int]struct{})
+
+func (port *legacyPort) Set(value string) error {
+	newKv := make(map[int]struct{})
+	port.normalizePortValue(newKv, value)
+
+	// if new values are provided, we remove default ones
+	if defaultValue, ok := legacyPortDefaultValues[port]; ok {
+		if maps.Equal(port.kv, defaultValue) {
+			port.kv = make(map[int]struct{})
+		}
+	}
+
+	port.kv = mapsutil.Merge(port.kv, newKv)
+
+	return nil
+}
+
+func (port *legacyPort) AsPorts() []int {
+	if port.kv == nil {
+		return nil
+	}
+	ports := make([]int, 0, len(port.kv))
+	for k := range port.kv {
+		ports = append(ports, k)
+	}
+	return ports
+}
+
+func (port *legacyPort) normalizePortValue(portsMap map[int]struct{}, value string) {
+	// Handle top-xxx/*/- cases
+	switch value {
+	case "full", "-", "*":
+		value = portsFull
+	case "top-100":
+		value = portsNmapTop100
+	case "top-1000":
+		value = portsNmapTop1000
+	}
+
+	values := strings.Split(value, ",")
+	for _, item := range values {
+		if ports, ok := lookupPortService(item); ok {
+			// Handle ftp,http,etc service names
+			port.appendPortsToKV(portsMap, ports)
+		} else if strings.Contains(item, ":") {
+			// Handle colon : based name like TCP:443
+			port.parsePortColonSeparated(portsMap, item)
+		} else if strings.HasSuffix(item, "*") {
+			// Handle wildcard service names
+			port.parseWildcardService(portsMap, item)
+		} else if strings.Contains(item, "-") {
+			// Handle dash based separated items
+			port.parsePortDashSeparated(portsMap, item)
+		} else {
+			// Handle normal ports
+			port.parsePortNumberItem(portsMap, item)
+		}
+	}
+}
+
+func (port *legacyPort) appendPortsToKV(portsMap map[int]struct{}, ports []int) {
+	for _, p := range ports {
+		portsMap[p] = struct{}{}
+	}
+}
+
+func (port *legacyPort) parseWildcardService(portsMap map[int]struct{}, item string) {
+	servicesMutex.RLock()
+	defer servicesMutex.RUnlock()
+
+	stripped := strings.TrimSuffix(item, "*")
+	for service, ports := range servicesMap {
+		if strings.HasPrefix(service, stripped) {
+			port.appendPortsToKV(portsMap, ports)
+		}
+	}
+}
+
+func (port *legacyPort) parsePortDashSeparated(portsMap map[int]struct{}, item string) {
+	parts := strings.Split(item, "-")
+	// Handle x- scenarios
+	if len(parts) == 2 && parts[1] == "" {
+		port.parsePortPairItems(portsMap, parts[0], "65535")
+	}
+	// Handle x-x port pairs
+	if len(parts) == 2 {
+		port.parsePortPairItems(portsMap, parts[0], parts[1])
+	}
+}
+
+func (port *legacyPort) parsePortColonSeparated(portsMap map[int]struct{}, item string) {
+	items := strings.Split(item, ":")
+	if len(items) == 2 {
+		parsed, err := strconv.Atoi(items[1])
+		if err == nil && parsed > 0 {
+			portsMap[parsed] = struct{}{}
+		}
+	}
+}
+
+func (port *legacyPort) parsePortNumberItem(portsMap map[int]struct{}, item string) {
+	parsed, err := strconv.Atoi(item)
+	if err == nil && parsed > 0 {
+		portsMap[parsed] = struct{}{}
+	}
+}
+
+func (port *legacyPort) parsePortPairItems(portsMap map[int]struct{}, first, second string) {
+	firstParsed, err := strconv.Atoi(first)
+	if err != nil {
+		return
+	}
+	secondParsed, err := strconv.Atoi(second)
+	if err != nil {
+		return
+	}
+	for i := firstParsed; i <= secondParsed; i++ {
+		portsMap[i] = struct{}{}
+	}
+}
+
+func BenchmarkPortSet(b *testing.B) {
+	values := []string{"full", "top-1000", "top-100", "http,ftp*,8000-8100,U:53"}
+	for _, value := range values {
+		b.Run(value+"/bitset", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				port := &Port{}
+				_ = port.Set(value)
+				_ = port.AsPorts()
+			}
+		})
+		b.Run(value+"/map", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				port := &legacyPort{}
+				_ = port.Set(value)
+				_ = port.AsPorts()
+			}
+		})
+	}
+}
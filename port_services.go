@@ -0,0 +1,180 @@
+package goflags
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/maps"
+)
+
+// RegisterPortService registers a service name usable wherever port flags accept
+// service names, including wildcards and protocol prefixes, e.g.
+// RegisterPortService("web", 80, 443, 8080, 8443) allows -p web and -p U:web.
+// A registered name replaces the ports of an existing service with the same name.
+//
+// Services are global to the process and shared by all the flag sets.
+func RegisterPortService(name string, ports ...int) {
+	if err := registerPortService(name, ports); err != nil {
+		panic(err)
+	}
+}
+
+func registerPortService(name string, ports []int) error {
+	if err := validatePortServiceName(name); err != nil {
+		return err
+	}
+	if len(ports) == 0 {
+		return fmt.Errorf("port service %s must have at least one port", name)
+	}
+	for _, p := range ports {
+		if p < 1 || p > 65535 {
+			return fmt.Errorf("port %d of service %s is out of range 1-65535", p, name)
+		}
+	}
+	servicesMutex.Lock()
+	servicesMap[name] = append([]int(nil), ports...)
+	servicesMutex.Unlock()
+	return nil
+}
+
+// validatePortServiceName checks that a service name can't be mistaken
+// for another item of a port value
+func validatePortServiceName(name string) error {
+	switch {
+	case name == "" || strings.ContainsAny(name, ", \t*"):
+		return fmt.Errorf("invalid port service name %q", name)
+	case strings.HasPrefix(name, "!"), name == "full", name == "-", strings.HasPrefix(name, "top-"):
+		return fmt.Errorf("port service name %q is reserved", name)
+	}
+	if _, err := strconv.Atoi(strings.SplitN(name, "-", 2)[0]); err == nil {
+		return fmt.Errorf("port service name %q can't start with a port number", name)
+	}
+	return nil
+}
+
+// LoadPortServices registers the services of a file in the /etc/services format,
+// e.g. "http 80/tcp www # WorldWideWeb HTTP". Aliases are registered as well and
+// the ports of all the lines of a service are merged. As with RegisterPortService,
+// the services are available to all the flag sets of the process.
+func LoadPortServices(filePath string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	services := make(map[string][]int)
+	var names []string
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 2 {
+			return fmt.Errorf("missing port for service %s on line %d of %s", fields[0], line, filePath)
+		}
+		portNumber, _, _ := strings.Cut(fields[1], "/")
+		p, err := parsePortNumber(portNumber)
+		if err != nil {
+			return fmt.Errorf("%v on line %d of %s", err, line, filePath)
+		}
+		for _, name := range append([]string{fields[0]}, fields[2:]...) {
+			if _, ok := services[name]; !ok {
+				names = append(names, name)
+			}
+			if !containsPort(services[name], p) {
+				services[name] = append(services[name], p)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	for _, name := range names {
+		if err := registerPortService(name, services[name]); err != nil {
+			return fmt.Errorf("%v in %s", err, filePath)
+		}
+	}
+	return nil
+}
+
+func containsPort(ports []int, p int) bool {
+	for _, current := range ports {
+		if current == p {
+			return true
+		}
+	}
+	return false
+}
+
+// SetPortServicesConfigKey sets the config file key holding user defined port services,
+// mapping names to port values, e.g.
+//
+//	port-services:
+//	  web: 80,443,8080,8443
+//	  admin: [8000-8100, 9090]
+//
+// Services have no protocol, so their values can't contain udp ports: the protocol
+// is given where the service is used instead, e.g. -p U:dns.
+// The services of the default config file are registered before the command line
+// is parsed, so they can be used in command line values as well. Services are global
+// to the process, so the services of a config file merged into a flag set are
+// available to all the flag sets.
+func (flagSet *FlagSet) SetPortServicesConfigKey(key string) {
+	flagSet.portServicesKey = key
+}
+
+// loadConfigFilePortServices registers the port services of a config file, if any
+func (flagSet *FlagSet) loadConfigFilePortServices(filePath string) error {
+	if flagSet.portServicesKey == "" {
+		return nil
+	}
+	data, err := readConfigData(filePath)
+	if err != nil {
+		return err
+	}
+	return flagSet.registerConfigPortServices(data)
+}
+
+// registerConfigPortServices registers the port services under the port services key of config data
+func (flagSet *FlagSet) registerConfigPortServices(data map[string]interface{}) error {
+	item, ok := data[flagSet.portServicesKey]
+	if flagSet.portServicesKey == "" || !ok {
+		return nil
+	}
+	services, ok := item.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("invalid value for %s in config: expected a map of service names to ports", flagSet.portServicesKey)
+	}
+	names := maps.Keys(services)
+	sort.Strings(names)
+	for _, name := range names {
+		var items []string
+		switch value := services[name].(type) {
+		case []interface{}:
+			for _, v := range value {
+				items = append(items, fmt.Sprint(v))
+			}
+		default:
+			items = append(items, fmt.Sprint(value))
+		}
+		port := &Port{}
+		if err := port.Set(strings.Join(items, ",")); err != nil {
+			return fmt.Errorf("invalid port service %s in config: %w", name, err)
+		}
+		// services have no protocol, it is given where the service is used
+		if len(port.AsUDPPorts()) > 0 {
+			return fmt.Errorf("invalid port service %s in config: udp ports are not supported, use a protocol prefix where the service is used (U:%s)", name, name)
+		}
+		if err := registerPortService(name, port.AsPorts()); err != nil {
+			return fmt.Errorf("invalid port service in config: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package goflags
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// unregisterPortServices removes services registered by a test once it completes
+func unregisterPortServices(t *testing.T, names ...string) {
+	t.Cleanup(func() {
+		servicesMutex.Lock()
+		defer servicesMutex.Unlock()
+		for _, name := range names {
+			delete(servicesMap, name)
+		}
+	})
+}
+
+func TestRegisterPortService(t *testing.T) {
+	unregisterPortServices(t, "goflags-web", "goflags-webadmin")
+	RegisterPortService("goflags-web", 80, 443, 8080, 8443)
+	RegisterPortService("goflags-webadmin", 9090)
+
+	port := &Port{}
+	require.Nil(t, port.Set("goflags-web,U:goflags-webadmin"))
+	require.Equal(t, []int{80, 443, 8080, 8443}, port.AsTCPPorts())
+	require.Equal(t, []int{9090}, port.AsUDPPorts())
+
+	port = &Port{}
+	require.Nil(t, port.Set("goflags-web*,!443"))
+	require.Equal(t, []int{80, 8080, 8443, 9090}, port.AsPorts())
+	require.Contains(t, portCompletionValues(), "goflags-web")
+
+	require.PanicsWithError(t, `port service name "top-web" is reserved`, func() { RegisterPortService("top-web", 80) })
+	require.PanicsWithError(t, `invalid port service name "a,b"`, func() { RegisterPortService("a,b", 80) })
+	require.PanicsWithError(t, `port service name "8080-alt" can't start with a port number`, func() { RegisterPortService("8080-alt", 8080) })
+	require.PanicsWithError(t, "port 70000 of service web is out of range 1-65535", func() { RegisterPortService("web", 70000) })
+	require.PanicsWithError(t, "port service web must have at least one port", func() { RegisterPortService("web") })
+}
+
+func TestRegisterPortServiceConcurrently(t *testing.T) {
+	unregisterPortServices(t, "goflags-concurrent")
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(p int) {
+			defer wg.Done()
+			RegisterPortService("goflags-concurrent", 8000+p)
+		}(i)
+		go func() {
+			defer wg.Done()
+			port := &Port{}
+			_ = port.Set("goflags-concurrent,http*")
+		}()
+	}
+	wg.Wait()
+
+	port := &Port{}
+	require.Nil(t, port.Set("goflags-concurrent"))
+	require.Equal(t, 1, port.Count())
+}
+
+func TestLoadPortServices(t *testing.T) {
+	unregisterPortServices(t, "goflags-http", "goflags-www", "goflags-dns")
+	servicesFile := filepath.Join(t.TempDir(), "services")
+	content := `# local services
+goflags-http	80/tcp		goflags-www	# WorldWideWeb HTTP
+goflags-http	8080/tcp
+goflags-dns	53/tcp
+goflags-dns	53/udp
+`
+	require.Nil(t, os.WriteFile(servicesFile, []byte(content), 0644))
+	require.Nil(t, LoadPortServices(servicesFile))
+
+	port := &Port{}
+	require.Nil(t, port.Set("goflags-www,goflags-http,U:goflags-dns"))
+	require.Equal(t, []int{80, 8080}, port.AsTCPPorts())
+	require.Equal(t, []int{53}, port.AsUDPPorts())
+
+	require.Nil(t, os.WriteFile(servicesFile, []byte("goflags-bad 99999/tcp\n"), 0644))
+	require.EqualError(t, LoadPortServices(servicesFile), "port 99999 is out of range 1-65535 on line 1 of "+servicesFile)
+}
+
+func TestPortServicesConfigKey(t *testing.T) {
+	unregisterPortServices(t, "goflags-web", "goflags-admin", "goflags-dns")
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	config := `port-services:
+  goflags-web: 80,443
+  goflags-admin: [8000-8002, 9090]
+`
+	require.Nil(t, os.WriteFile(configFile, []byte(config), 0644))
+
+	flagSet := NewFlagSet()
+	flagSet.SetConfigFilePath(configFile)
+	flagSet.SetPortServicesConfigKey("port-services")
+	var port Port
+	flagSet.PortVarP(&port, "port", "p", nil, "ports to scan")
+	require.Nil(t, flagSet.Parse("-p", "goflags-web,goflags-admin"))
+	require.Equal(t, []int{80, 443, 8000, 8001, 8002, 9090}, port.AsPorts())
+
+	require.Nil(t, os.WriteFile(configFile, []byte("port-services:\n  goflags-web: 70000\n"), 0644))
+	require.EqualError(t, flagSet.MergeConfigFile(configFile), "invalid port service goflags-web in config: port 70000 is out of range 1-65535")
+
+	flagSet = NewFlagSet()
+	flagSet.SetConfigFilePath(configFile)
+	flagSet.SetPortServicesConfigKey("port-services")
+	flagSet.PortVarP(&port, "port", "p", nil, "ports to scan")
+	require.EqualError(t, flagSet.Parse("-p", "80"), "invalid port service goflags-web in config: port 70000 is out of range 1-65535", "invalid services of the default config should be returned by Parse")
+
+	require.Nil(t, os.WriteFile(configFile, []byte("port-services:\n  goflags-web: 80\n  goflags-dns: U:53\n"), 0644))
+	flagSet = NewFlagSet()
+	flagSet.SetConfigFilePath(configFile)
+	flagSet.SetPortServicesConfigKey("port-services")
+	flagSet.PortVarP(&port, "port", "p", nil, "ports to scan")
+	require.EqualError(t, flagSet.Parse("-p", "80"), "invalid port service goflags-dns in config: udp ports are not supported, use a protocol prefix where the service is used (U:goflags-dns)", "udp ports should not be registered as protocol-less ports")
+	_, ok := lookupPortService("goflags-dns")
+	require.False(t, ok)
+
+	require.Nil(t, os.WriteFile(configFile, []byte("port-services:\n  goflags-dns: 53\n"), 0644))
+	port = Port{}
+	flagSet = NewFlagSet()
+	flagSet.SetConfigFilePath(configFile)
+	flagSet.SetPortServicesConfigKey("port-services")
+	flagSet.PortVarP(&port, "port", "p", nil, "ports to scan")
+	require.Nil(t, flagSet.Parse("-p", "U:goflags-dns"))
+	require.Equal(t, []PortEntry{{Port: 53, Protocol: UDP}}, port.AsPortsWithProtocol())
+
+	tearDown(t.Name())
+}
@@ -1,6 +1,9 @@
 package goflags
 
 import (
+	"flag"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -59,4 +62,161 @@ func TestPortType(t *testing.T) {
 		_ = port.Set("TCP:443,UDP:53")
 		require.ElementsMatch(t, port.AsPorts(), []int{443, 53}, "could not get correct ports")
 	})
+	t.Run("protocol", func(t *testing.T) {
+		port := &Port{}
+		_ = port.Set("U:53,T:25,80,udp:161-162")
+		require.Equal(t, []PortEntry{{25, TCP}, {53, UDP}, {80, TCP}, {161, UDP}, {162, UDP}}, port.AsPortsWithProtocol())
+		require.Equal(t, []int{25, 80}, port.AsTCPPorts())
+		require.Equal(t, []int{53, 161, 162}, port.AsUDPPorts())
+	})
+	t.Run("protocol-services", func(t *testing.T) {
+		port := &Port{}
+		_ = port.Set("U:domain,T:1-3,T:domain")
+		require.Equal(t, []int{1, 2, 3, 53}, port.AsTCPPorts())
+		require.Equal(t, []int{53}, port.AsUDPPorts())
+		require.ElementsMatch(t, []int{1, 2, 3, 53}, port.AsPorts(), "ports given for both protocols should be listed once")
+	})
+	t.Run("exclusions", func(t *testing.T) {
+		port := &Port{}
+		require.Nil(t, port.Set("top-1000,!22,!8000-8100"))
+		require.Contains(t, port.AsPorts(), 443)
+		for _, p := range port.AsPorts() {
+			require.False(t, p == 22 || p >= 8000 && p <= 8100, "port %d should be excluded", p)
+		}
+
+		port = &Port{}
+		require.Nil(t, port.Set("!80,U:80,80,443,!U:443,U:443"))
+		require.Equal(t, []int{443}, port.AsTCPPorts(), "exclusions without protocol should apply to both protocols")
+		require.Empty(t, port.AsUDPPorts())
+
+		port = &Port{}
+		require.Nil(t, port.Set("!22"))
+		require.Nil(t, port.Set("21-23"))
+		require.Equal(t, []int{21, 23}, port.AsTCPPorts(), "exclusions should apply whatever the order of values")
+	})
+	t.Run("exclusions-default", func(t *testing.T) {
+		tests := []struct {
+			args     []string
+			expected []int
+		}{
+			{[]string{"-p", "!22"}, []int{21, 23}},
+			{[]string{"-p", "!22", "-p", "80"}, []int{80}},
+			{[]string{"-p", "80", "-p", "!22"}, []int{80}},
+			{[]string{"-p", "!22", "-p", "20-25"}, []int{20, 21, 23, 24, 25}},
+			{[]string{"-p", "23", "-p", "21"}, []int{21, 23}},
+		}
+		for _, test := range tests {
+			flagSet := NewFlagSet()
+			flagSet.SetConfigFilePath(filepath.Join(t.TempDir(), "config.yaml"))
+			var port Port
+			flagSet.PortVarP(&port, "port", "p", []string{"20-23,!20"}, "ports to scan")
+			require.Nil(t, flagSet.Parse(test.args...))
+			require.Equal(t, test.expected, port.AsTCPPorts(), "unexpected ports for %v", test.args)
+		}
+		tearDown(t.Name())
+	})
+	t.Run("parse-errors", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		flagSet.CommandLine.Init(os.Args[0], flag.ContinueOnError)
+		flagSet.SetConfigFilePath(filepath.Join(t.TempDir(), "config.yaml"))
+		var port Port
+		var threads int
+		flagSet.PortVarP(&port, "port", "p", []string{"80"}, "ports to scan")
+		flagSet.IntVarP(&threads, "threads", "n", 10, "number of threads")
+
+		require.EqualError(t, flagSet.Parse("-p", "abc"), `invalid value "abc" for flag -p: invalid port "abc"`)
+		require.Equal(t, []int{80}, port.AsPorts(), "invalid values should keep the default ports")
+		require.EqualError(t, flagSet.Parse("-n", "x"), `invalid value "x" for flag -n: parse error`)
+		tearDown(t.Name())
+	})
+	t.Run("errors", func(t *testing.T) {
+		tests := map[string]string{
+			"80,abc":    `invalid port "abc"`,
+			"70000":     "port 70000 is out of range 1-65535",
+			"0":         "port 0 is out of range 1-65535",
+			"100-10":    "invalid port range 100-10: start is greater than end",
+			"1-2-3":     `invalid port range "1-2-3"`,
+			"X:443":     `unknown protocol "X" in "X:443", expected T, TCP, U or UDP`,
+			"nosuchsv*": `no service matches "nosuchsv*"`,
+			"!U:65536":  "port 65536 is out of range 1-65535",
+		}
+		for value, expected := range tests {
+			port := &Port{}
+			require.EqualError(t, port.Set(value), expected, "unexpected error for %q", value)
+			require.Nil(t, port.AsPorts(), "invalid values should not set any port")
+		}
+		require.PanicsWithError(t, `invalid default value "65536" for flag -port: port 65536 is out of range 1-65535`, func() {
+			var port Port
+			NewFlagSet().PortVar(&port, "port", []string{"65536"}, "ports to scan")
+		})
+	})
+	t.Run("top-n", func(t *testing.T) {
+		port := &Port{}
+		require.Nil(t, port.Set("top-50"))
+		require.Len(t, port.AsPorts(), 50)
+		require.Subset(t, portsFromList(t, portsNmapTop100), port.AsPorts())
+
+		port = &Port{}
+		require.Nil(t, port.Set("top-1000"))
+		require.Equal(t, portsFromList(t, portsNmapTop1000), port.AsTCPPorts())
+
+		port = &Port{}
+		require.Nil(t, port.Set("top-65535"))
+		require.Len(t, port.AsPorts(), 65535)
+
+		port = &Port{}
+		require.Nil(t, port.Set("top-100-udp"))
+		require.Empty(t, port.AsTCPPorts())
+		require.ElementsMatch(t, portsFromList(t, portsNmapTopUDP100), port.AsUDPPorts())
+
+		port = &Port{}
+		require.Nil(t, port.Set("top-10-udp,top-10-tcp"))
+		require.Equal(t, []int{53, 67, 123, 135, 137, 138, 161, 445, 631, 1434}, port.AsUDPPorts())
+		require.Equal(t, []int{21, 22, 23, 25, 80, 110, 139, 443, 445, 3389}, port.AsTCPPorts())
+
+		for _, value := range []string{"top-0", "top-65536", "top-x", "top-10-sctp"} {
+			require.Error(t, (&Port{}).Set(value), "expected an error for %q", value)
+		}
+		for _, value := range []string{"top-500", "top-5000", "top-101-udp"} {
+			require.ErrorContains(t, (&Port{}).Set(value), "the frequency data ranks the", "counts beyond the ranked ports should not return arbitrary ports for %q", value)
+		}
+	})
+	t.Run("top-ranking", func(t *testing.T) {
+		tcp := rankedPorts(TCP)
+		require.Equal(t, []int{80, 23, 443, 21, 22, 25, 3389, 110, 445, 139, 143, 53, 135, 3306, 8080, 1723, 111, 995, 993, 5900}, tcp[:20], "tcp ports should be ranked by frequency")
+		require.ElementsMatch(t, portsFromList(t, portsNmapTop100), tcp[:100])
+		require.Subset(t, portsFromList(t, portsNmapTop1000), tcp)
+
+		udp := rankedPorts(UDP)
+		require.Equal(t, []int{631, 161, 137, 123, 138, 1434, 445, 135, 67, 53, 139, 500, 68, 520, 1900, 4500, 514, 49152, 162, 69}, udp[:20], "udp ports should be ranked by frequency")
+		require.ElementsMatch(t, portsFromList(t, portsNmapTopUDP100), udp[:100])
+
+		for _, ranking := range [][]int{tcp, udp} {
+			seen := make(map[int]struct{})
+			for _, p := range ranking {
+				require.True(t, p >= 1 && p <= 65535, "ranked port %d is out of range", p)
+				require.NotContains(t, seen, p, "port %d is ranked twice", p)
+				seen[p] = struct{}{}
+			}
+		}
+	})
+	t.Run("protocol-top", func(t *testing.T) {
+		port := &Port{}
+		_ = port.Set("U:top-100,8443")
+		require.Len(t, port.AsUDPPorts(), 100)
+		require.Equal(t, []int{8443}, port.AsTCPPorts())
+	})
+}
+
+// portsFromList returns the ports of a comma separated list of ports and ranges
+func portsFromList(t *testing.T, list string) []int {
+	port := &Port{}
+	require.Nil(t, port.Set(list))
+	return port.AsPorts()
 }
+
+// nmap top ports used as fixtures for the frequency data
+const (
+	portsNmapTop100    = "7,9,13,21-23,25-26,37,53,79-81,88,106,110-111,113,119,135,139,143-144,179,199,389,427,443-445,465,513-515,543-544,548,554,587,631,646,873,990,993,995,1025-1029,1110,1433,1720,1723,1755,1900,2000-2001,2049,2121,2717,3000,3128,3306,3389,3986,4899,5000,5009,5051,5060,5101,5190,5357,5432,5631,5666,5800,5900,6000-6001,6646,7070,8000,8008-8009,8080-8081,8443,8888,9100,9999-10000,32768,49152-49157"
+	portsNmapTopUDP100 = "7,9,17,19,49,53,67-69,80,88,111,120,123,135-139,158,161-162,177,427,443,445,497,500,514-515,518,520,593,623,626,631,996-999,1022-1023,1025-1030,1433-1434,1645-1646,1701,1718-1719,1812-1813,1900,2000,2048-2049,2222-2223,3283,3456,3703,4444,4500,5000,5060,5353,5632,9200,10000,17185,20031,30718,31337,32768-32769,32771,32815,33281,49152-49154,49156,49181-49182,49185-49186,49188,49190-49194,49200-49201,65024"
+)
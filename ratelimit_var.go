@@ -129,7 +129,7 @@ func (flagSet *FlagSet) RateLimitMapVarP(field *RateLimitMap, long, short string
 	if short != "" {
 		flagData.short = short
 		flagSet.CommandLine.Var(field, short, usage)
-		flagSet.flagKeys.Set(short, flagData)
+		flagSet.setShortFlag(short, flagData)
 	}
 	flagSet.CommandLine.Var(field, long, usage)
 	flagSet.flagKeys.Set(long, flagData)
@@ -49,7 +49,7 @@ func (flagSet *FlagSet) SizeVarP(field *Size, long, short string, defaultValue s
 	if short != "" {
 		flagData.short = short
 		flagSet.CommandLine.Var(field, short, usage)
-		flagSet.flagKeys.Set(short, flagData)
+		flagSet.setShortFlag(short, flagData)
 	}
 	flagSet.CommandLine.Var(field, long, usage)
 	flagSet.flagKeys.Set(long, flagData)
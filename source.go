@@ -0,0 +1,38 @@
+package goflags
+
+import "flag"
+
+// ValueSource is the origin of the effective value of a flag
+type ValueSource string
+
+const (
+	// SourceDefault is used when the flag keeps its default value
+	SourceDefault ValueSource = "default"
+	// SourceEnv is used when the value was read from an environment variable
+	SourceEnv ValueSource = "env"
+	// SourceConfig is used when the value was read from the config file
+	SourceConfig ValueSource = "config"
+	// SourceCLI is used when the value was given on the command line
+	SourceCLI ValueSource = "cli"
+	// SourceImplied is used when the value was set by a flag implying it
+	SourceImplied ValueSource = "implied"
+)
+
+// Source returns where the effective value of the flag came from
+func (flagData *FlagData) Source() ValueSource {
+	if flagData.source == "" {
+		return SourceDefault
+	}
+	return flagData.source
+}
+
+// markCommandLineSources records the flags that were given on the command line
+func (flagSet *FlagSet) markCommandLineSources() {
+	flagSet.CommandLine.Visit(func(fl *flag.Flag) {
+		if data, ok := flagSet.flagKeys.values[fl.Name]; ok {
+			data.source = SourceCLI
+		} else if negation, ok := fl.Value.(*negatedBool); ok {
+			negation.data.source = SourceCLI
+		}
+	})
+}
@@ -0,0 +1,40 @@
+package goflags
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValueSource(t *testing.T) {
+	t.Setenv("GOFLAGS_TEST_TOKEN", "env-token")
+
+	flagSet := NewFlagSet()
+	var target, token, output string
+	var threads, retries int
+	targetFlag := flagSet.StringVarP(&target, "target", "u", "", "target to scan")
+	tokenFlag := flagSet.StringVarEnv(&token, "token", "tk", "", "GOFLAGS_TEST_TOKEN", "api token")
+	outputFlag := flagSet.StringVarP(&output, "output", "o", "", "output file")
+	threadsFlag := flagSet.IntVarP(&threads, "threads", "c", 25, "number of threads")
+	retriesFlag := flagSet.IntVar(&retries, "retries", 1, "number of retries")
+
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.Nil(t, os.WriteFile(configFile, []byte("threads: 50\noutput: config.txt\n"), 0644))
+
+	require.Nil(t, flagSet.CommandLine.Parse([]string{"-u", "example.com", "-o", "cli.txt"}))
+	flagSet.markCommandLineSources()
+	require.Nil(t, flagSet.MergeConfigFile(configFile))
+
+	require.Equal(t, SourceCLI, targetFlag.Source())
+	require.Equal(t, SourceEnv, tokenFlag.Source())
+	require.Equal(t, SourceConfig, threadsFlag.Source())
+	require.Equal(t, SourceDefault, retriesFlag.Source())
+
+	require.Equal(t, SourceCLI, outputFlag.Source(), "config should not override command line values")
+	require.Equal(t, "cli.txt", output)
+	require.Equal(t, 50, threads)
+
+	tearDown(t.Name())
+}
@@ -0,0 +1,53 @@
+package goflags
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Required marks the flag as required. Parse returns an error if the flag
+// was not given on the command line, through its environment variable or
+// in the config file.
+func (flagData *FlagData) Required() *FlagData {
+	flagData.required = true
+	return flagData
+}
+
+// validateFlags applies the implied values and checks the rules of the flags
+// once all the value sources are merged
+func (flagSet *FlagSet) validateFlags() error {
+	errs := flagSet.applyImplications()
+	if err := flagSet.validateRequiredFlags(); err != nil {
+		errs = append(errs, err)
+	}
+	for _, constraint := range flagSet.constraints {
+		if err := flagSet.validateConstraint(constraint); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	errs = append(errs, flagSet.validateDependencies()...)
+	errs = append(errs, flagSet.validateValues()...)
+	errs = append(errs, flagSet.bindArgs()...)
+	return errors.Join(errs...)
+}
+
+// validateRequiredFlags returns a single error listing all the missing required flags
+func (flagSet *FlagSet) validateRequiredFlags() error {
+	var missing []string
+	uniqueDeduper := newUniqueDeduper()
+	flagSet.flagKeys.forEach(func(key string, data *FlagData) {
+		if !data.required || data.Source() != SourceDefault || !uniqueDeduper.isUnique(data) {
+			return
+		}
+		name := flagSet.joinFlagNames(data)
+		if data.group != "" {
+			name += " (" + flagSet.groupDescription(data.group) + ")"
+		}
+		missing = append(missing, name)
+	})
+	if len(missing) == 0 {
+		return nil
+	}
+	return fmt.Errorf("missing required flags:\n  %s", strings.Join(missing, "\n  "))
+}
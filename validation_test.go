@@ -0,0 +1,57 @@
+package goflags
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequiredFlags(t *testing.T) {
+	t.Run("missing", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		flagSet.SetConfigFilePath(filepath.Join(t.TempDir(), "config.yaml"))
+		var target, output, token string
+		flagSet.CreateGroup("input", "Input",
+			flagSet.StringVarP(&target, "target", "u", "", "target to scan").Required(),
+		)
+		flagSet.StringVarP(&output, "output", "o", "", "output file").Required()
+		flagSet.StringVarEnv(&token, "token", "tk", "", "GOFLAGS_REQUIRED_TOKEN", "api token").Required()
+
+		os.Args = []string{os.Args[0]}
+		err := flagSet.Parse()
+		require.NotNil(t, err)
+		require.Equal(t, "missing required flags:\n  -u, -target (INPUT)\n  -o, -output\n  -tk, -token", err.Error())
+		tearDown(t.Name())
+	})
+
+	t.Run("satisfied", func(t *testing.T) {
+		t.Setenv("GOFLAGS_REQUIRED_TOKEN", "secret")
+		flagSet := NewFlagSet()
+		flagSet.SetConfigFilePath(filepath.Join(t.TempDir(), "config.yaml"))
+		var target, output, token string
+		flagSet.StringVarP(&target, "target", "u", "", "target to scan").Required()
+		flagSet.StringVarP(&output, "output", "o", "", "output file").Required()
+		flagSet.StringVarEnv(&token, "token", "tk", "", "GOFLAGS_REQUIRED_TOKEN", "api token").Required()
+
+		err := flagSet.Parse("-u", "example.com", "-o", "output.txt")
+		require.Nil(t, err)
+		require.Equal(t, "secret", token)
+		tearDown(t.Name())
+	})
+
+	t.Run("usage", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		var target string
+		flagSet.StringVarP(&target, "target", "u", "", "target to scan").Required()
+
+		output := &bytes.Buffer{}
+		flagSet.CommandLine.SetOutput(output)
+		os.Args = []string{os.Args[0], "-h"}
+		flagSet.usageFunc()
+		require.Contains(t, output.String(), "-u, -target string  target to scan (required)\n")
+		tearDown(t.Name())
+	})
+}
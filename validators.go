@@ -0,0 +1,167 @@
+package goflags
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	fileutil "github.com/projectdiscovery/utils/file"
+)
+
+// Validator checks a value of a flag, returning an error describing why it is invalid.
+// For string slice flags, each item of the slice is validated.
+type Validator func(value string) error
+
+// Validate adds validators run on the value of the flag once all the value sources
+// are merged. Default values are not validated.
+func (flagData *FlagData) Validate(validators ...Validator) *FlagData {
+	flagData.validators = append(flagData.validators, validators...)
+	return flagData
+}
+
+// validateValues runs the validators of the flags set by any source
+func (flagSet *FlagSet) validateValues() []error {
+	var errs []error
+	uniqueDeduper := newUniqueDeduper()
+	flagSet.flagKeys.forEach(func(key string, data *FlagData) {
+		if len(data.validators) == 0 || data.Source() == SourceDefault || !uniqueDeduper.isUnique(data) {
+			return
+		}
+		currentFlag := flagSet.lookupFlag(data)
+		if currentFlag == nil {
+			return
+		}
+		values := []string{currentFlag.Value.String()}
+		if slice, ok := currentFlag.Value.(*StringSlice); ok {
+			values = *slice
+		}
+		for _, value := range values {
+			for _, validator := range data.validators {
+				if err := validator(value); err != nil {
+					errs = append(errs, fmt.Errorf("invalid value %q for flag %s (%s): %v", value, flagSet.flagName(primaryName(data)), data.Source(), err))
+					break
+				}
+			}
+		}
+	})
+	return errs
+}
+
+// InRange returns a validator accepting numbers between min and max, inclusive
+func InRange(min, max float64) Validator {
+	return func(value string) error {
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return errors.New("must be a number")
+		}
+		if number < min || number > max {
+			return fmt.Errorf("must be between %v and %v", min, max)
+		}
+		return nil
+	}
+}
+
+// MatchRegex returns a validator accepting values matching the regular expression.
+// It panics if the expression cannot be compiled.
+func MatchRegex(pattern string) Validator {
+	re := regexp.MustCompile(pattern)
+	return func(value string) error {
+		if !re.MatchString(value) {
+			return fmt.Errorf("must match %s", pattern)
+		}
+		return nil
+	}
+}
+
+// OneOf returns a validator accepting only the given values
+func OneOf(values ...string) Validator {
+	return func(value string) error {
+		for _, allowed := range values {
+			if value == allowed {
+				return nil
+			}
+		}
+		return fmt.Errorf("must be one of %s", strings.Join(values, ", "))
+	}
+}
+
+// ExistingFile accepts paths of existing files
+func ExistingFile(value string) error {
+	if !fileutil.FileExists(value) {
+		return errors.New("file does not exist")
+	}
+	return nil
+}
+
+// ExistingDir accepts paths of existing directories
+func ExistingDir(value string) error {
+	if !fileutil.FolderExists(value) {
+		return errors.New("directory does not exist")
+	}
+	return nil
+}
+
+// WritablePath accepts paths of writable files or directories, and paths
+// which can be created in a writable directory
+func WritablePath(value string) error {
+	info, err := os.Stat(value)
+	switch {
+	case err == nil && info.IsDir():
+		return checkWritableDir(value)
+	case err == nil:
+		file, err := os.OpenFile(value, os.O_WRONLY, 0)
+		if err != nil {
+			return errors.New("file is not writable")
+		}
+		return file.Close()
+	case os.IsNotExist(err):
+		return checkWritableDir(filepath.Dir(value))
+	default:
+		return err
+	}
+}
+
+// checkWritableDir checks that a file can be created in the directory
+func checkWritableDir(dir string) error {
+	file, err := os.CreateTemp(dir, ".goflags-*")
+	if err != nil {
+		return fmt.Errorf("directory %s is not writable", dir)
+	}
+	_ = file.Close()
+	return os.Remove(file.Name())
+}
+
+// ValidURL accepts absolute URLs with a scheme and a host
+func ValidURL(value string) error {
+	parsed, err := url.Parse(value)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return errors.New("must be a URL with a scheme and host")
+	}
+	return nil
+}
+
+// ValidCIDR accepts IPv4 and IPv6 CIDR ranges
+func ValidCIDR(value string) error {
+	if _, _, err := net.ParseCIDR(value); err != nil {
+		return errors.New("must be a CIDR range")
+	}
+	return nil
+}
+
+// ValidHostPort accepts host:port values with a port between 1 and 65535
+func ValidHostPort(value string) error {
+	host, port, err := net.SplitHostPort(value)
+	if err != nil || host == "" {
+		return errors.New("must be in host:port format")
+	}
+	if number, err := strconv.Atoi(port); err != nil || number < 1 || number > 65535 {
+		return errors.New("port must be between 1 and 65535")
+	}
+	return nil
+}
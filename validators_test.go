@@ -0,0 +1,98 @@
+package goflags
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlagValidators(t *testing.T) {
+	dir := t.TempDir()
+	existingFile := filepath.Join(dir, "templates.yaml")
+	require.Nil(t, os.WriteFile(existingFile, []byte("id: test"), 0644))
+
+	t.Run("valid", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		flagSet.SetConfigFilePath(filepath.Join(t.TempDir(), "config.yaml"))
+		var concurrency int
+		var templates StringSlice
+		var output, proxy string
+		flagSet.IntVarP(&concurrency, "concurrency", "c", -1, "concurrent requests").Validate(InRange(1, 100))
+		flagSet.StringSliceVarP(&templates, "templates", "t", nil, "templates to run", CommaSeparatedStringSliceOptions).Validate(ExistingFile)
+		flagSet.StringVarP(&output, "output", "o", "", "output directory").Validate(ExistingDir)
+		flagSet.StringVar(&proxy, "proxy", "", "proxy to use").Validate(ValidURL)
+
+		require.Nil(t, flagSet.Parse("-c", "10", "-t", existingFile, "-o", dir, "-proxy", "http://127.0.0.1:8080"))
+		require.Equal(t, 10, concurrency)
+		tearDown(t.Name())
+	})
+
+	t.Run("defaults not validated", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		flagSet.SetConfigFilePath(filepath.Join(t.TempDir(), "config.yaml"))
+		var concurrency int
+		var output string
+		flagSet.IntVarP(&concurrency, "concurrency", "c", -1, "concurrent requests").Validate(InRange(1, 100))
+		flagSet.StringVarP(&output, "output", "o", "", "output directory").Validate(ExistingDir)
+
+		os.Args = []string{os.Args[0]}
+		require.Nil(t, flagSet.Parse())
+		tearDown(t.Name())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		configFile := filepath.Join(t.TempDir(), "config.yaml")
+		require.Nil(t, os.WriteFile(configFile, []byte("proxy: 127.0.0.1\n"), 0644))
+
+		flagSet := NewFlagSet()
+		flagSet.SetConfigFilePath(configFile)
+		var concurrency int
+		var templates StringSlice
+		var proxy string
+		flagSet.IntVarP(&concurrency, "concurrency", "c", -1, "concurrent requests").Validate(InRange(1, 100))
+		flagSet.StringSliceVarP(&templates, "templates", "t", nil, "templates to run", CommaSeparatedStringSliceOptions).Validate(ExistingFile)
+		flagSet.StringVar(&proxy, "proxy", "", "proxy to use").Validate(ValidURL)
+
+		missingFile := filepath.Join(dir, "missing.yaml")
+		err := flagSet.Parse("-c", "0", "-t", existingFile+","+missingFile)
+		require.NotNil(t, err)
+		require.Equal(t, `invalid value "0" for flag -concurrency (cli): must be between 1 and 100`+"\n"+
+			`invalid value "`+missingFile+`" for flag -templates (cli): file does not exist`+"\n"+
+			`invalid value "127.0.0.1" for flag -proxy (config): must be a URL with a scheme and host`, err.Error())
+		tearDown(t.Name())
+	})
+}
+
+func TestBuiltinValidators(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name      string
+		validator Validator
+		valid     []string
+		invalid   []string
+	}{
+		{"InRange", InRange(1, 10), []string{"1", "5", "10", "2.5"}, []string{"0", "11", "ten"}},
+		{"MatchRegex", MatchRegex(`^[a-z]+$`), []string{"abc"}, []string{"ABC", "a1"}},
+		{"OneOf", OneOf("json", "csv"), []string{"json", "csv"}, []string{"xml", ""}},
+		{"ExistingDir", ExistingDir, []string{dir}, []string{filepath.Join(dir, "missing")}},
+		{"WritablePath", WritablePath, []string{dir, filepath.Join(dir, "new.txt")}, []string{filepath.Join(dir, "missing", "new.txt")}},
+		{"ValidURL", ValidURL, []string{"https://example.com/path"}, []string{"example.com", "https://", "://bad"}},
+		{"ValidCIDR", ValidCIDR, []string{"10.0.0.0/8", "2001:db8::/32"}, []string{"10.0.0.1", "10.0.0.0/33"}},
+		{"ValidHostPort", ValidHostPort, []string{"example.com:443", "[::1]:8080"}, []string{"example.com", ":80", "host:0", "host:http"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, value := range test.valid {
+				require.Nil(t, test.validator(value), "value %q should be valid", value)
+			}
+			for _, value := range test.invalid {
+				require.NotNil(t, test.validator(value), "value %q should be invalid", value)
+			}
+		})
+	}
+
+	tearDown(t.Name())
+}